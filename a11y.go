@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Image Accessibility
+// ------------------------------------------------------------------------------------------------
+
+const (
+	altMinLength = 5   // shorter alt text rarely describes anything
+	altMaxLength = 420 // X's limit; screen readers cope poorly beyond it
+)
+
+// imageGroup is one declared preview image with its alt text. OG structured
+// properties attach og:image:alt to the most recent og:image, so repeated
+// images each get their own group.
+type imageGroup struct {
+	Property string // "og:image" or "twitter:image"
+	URL      string
+	Alt      string
+	HasAlt   bool
+}
+
+// imageGroups walks the meta tags in document order and groups every
+// og:image / twitter:image with its alt text.
+func imageGroups(html string) []imageGroup {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var groups []imageGroup
+	current := map[string]int{"og:image": -1, "twitter:image": -1}
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		key, ok := s.Attr("property")
+		if !ok {
+			key, _ = s.Attr("name")
+		}
+		content, _ := s.Attr("content")
+		switch key {
+		case "og:image", "og:image:url", "twitter:image", "twitter:image:src":
+			base := strings.TrimSuffix(strings.TrimSuffix(key, ":url"), ":src")
+			// og:image:url directly after og:image describes the same image.
+			if i := current[base]; key == "og:image:url" && i >= 0 && groups[i].URL == content {
+				return
+			}
+			groups = append(groups, imageGroup{Property: base, URL: content})
+			current[base] = len(groups) - 1
+		case "og:image:alt", "twitter:image:alt":
+			base := strings.TrimSuffix(key, ":alt")
+			if i := current[base]; i >= 0 {
+				groups[i].Alt, groups[i].HasAlt = strings.TrimSpace(content), true
+			}
+		}
+	})
+	return groups
+}
+
+// altTextFindings checks every image for alt text that is present, not just
+// the file name or the title, and within a sensible length.
+func altTextFindings(groups []imageGroup, titles ...string) []finding {
+	var out []finding
+	for _, g := range groups {
+		label := g.Property
+		if len(groups) > 1 {
+			label = fmt.Sprintf("%s %s", g.Property, g.URL)
+		}
+		alt := strings.ToLower(strings.Join(strings.Fields(g.Alt), " "))
+		switch n := utf8.RuneCountInString(g.Alt); {
+		case !g.HasAlt || n == 0:
+			out = append(out, warnf("alt-missing", "%s has no %s:alt text", label, g.Property))
+			continue
+		case n < altMinLength:
+			out = append(out, warnf("alt-length", "%s:alt is too short (%d characters, minimum %d)", label, n, altMinLength))
+		case n > altMaxLength:
+			out = append(out, warnf("alt-length", "%s:alt is too long (%d characters, maximum %d)", label, n, altMaxLength))
+		}
+		if name := fileStem(g.URL); name != "" && (alt == name || alt == strings.ToLower(path.Base(urlPath(g.URL)))) {
+			out = append(out, warnf("alt-filename", "%s:alt just repeats the file name", label))
+		}
+		for _, t := range titles {
+			if t = strings.ToLower(strings.Join(strings.Fields(t), " ")); t != "" && alt == t {
+				out = append(out, warnf("alt-title", "%s:alt just repeats the title; describe the image instead", label))
+				break
+			}
+		}
+	}
+	return out
+}
+
+// urlPath returns the path component of raw, or raw itself when unparsable.
+func urlPath(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.Path
+	}
+	return raw
+}
+
+// fileStem returns the image file name without extension, with separators
+// turned into spaces ("og-hero_image.png" → "og hero image").
+func fileStem(raw string) string {
+	base := path.Base(urlPath(raw))
+	if base == "." || base == "/" {
+		return ""
+	}
+	base = strings.TrimSuffix(base, path.Ext(base))
+	return strings.ToLower(strings.Join(strings.FieldsFunc(base, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == '+'
+	}), " "))
+}
+
+// pixelFindings downloads and decodes the image and applies two heuristics:
+// low overall contrast and text baked into the picture.
+func pixelFindings(ctx context.Context, imgURL string) ([]finding, error) {
+	data, _, err := fetchImageBytes(ctx, imgURL)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode og:image for pixel checks: %w", err)
+	}
+
+	var out []finding
+	if ratio := contrastRatio(img); ratio < 3 {
+		out = append(out, warnf("a11y-contrast", "og:image has low contrast (%.1f:1 between its dark and light tones, WCAG asks ≥ 3:1 for large text)", ratio))
+	}
+	if density := edgeDensity(img); density > 0.12 {
+		out = append(out, warnf("a11y-baked-text", "og:image appears to contain baked-in text (edge density %.0f%%); make sure the alt text conveys it", density*100))
+	}
+	return out, nil
+}
+
+// contrastRatio estimates the WCAG contrast ratio between the 5th and 95th
+// percentile of relative luminance.
+func contrastRatio(img image.Image) float64 {
+	b := img.Bounds()
+	w := min(b.Dx(), 256)
+	h := max(1, w*b.Dy()/max(b.Dx(), 1))
+	grey := greyscale(img, w, h)
+	lum := make([]float64, len(grey))
+	for i, v := range grey {
+		c := v / 255
+		if c <= 0.04045 {
+			lum[i] = c / 12.92
+		} else {
+			lum[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	sort.Float64s(lum)
+	lo, hi := lum[len(lum)*5/100], lum[len(lum)*95/100]
+	return (hi + 0.05) / (lo + 0.05)
+}
+
+// edgeDensity is the share of horizontally adjacent pixels with a sharp
+// luminance step; text produces many of them, photos and gradients few.
+func edgeDensity(img image.Image) float64 {
+	b := img.Bounds()
+	w := min(b.Dx(), 400)
+	h := max(1, w*b.Dy()/max(b.Dx(), 1))
+	grey := greyscale(img, w, h)
+	var edges, total int
+	for y := 0; y < h; y++ {
+		for x := 0; x+1 < w; x++ {
+			if math.Abs(grey[y*w+x+1]-grey[y*w+x]) > 60 {
+				edges++
+			}
+			total++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(edges) / float64(total)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestImageGroups(t *testing.T) {
+	html := `<html><head>
+	<meta property="og:image" content="https://cdn.example.com/hero-banner.png">
+	<meta property="og:image:alt" content="hero banner">
+	<meta property="og:image" content="https://cdn.example.com/second.png">
+	<meta name="twitter:image" content="https://cdn.example.com/card.jpg">
+	<meta name="twitter:image:alt" content="Team photo in front of the Berlin office">
+	</head></html>`
+	groups := imageGroups(html)
+	if len(groups) != 3 {
+		t.Fatalf("imageGroups = %+v, want 3 groups", groups)
+	}
+	if groups[0].Alt != "hero banner" || groups[1].HasAlt || !groups[2].HasAlt {
+		t.Errorf("alt text attached to the wrong image: %+v", groups)
+	}
+
+	findings := strings.Join(messages(altTextFindings(groups, "Title")), "\n")
+	for _, want := range []string{"repeats the file name", "second.png has no og:image:alt"} {
+		if !strings.Contains(findings, want) {
+			t.Errorf("altTextFindings: missing %q in\n%s", want, findings)
+		}
+	}
+	if strings.Contains(findings, "card.jpg") {
+		t.Errorf("altTextFindings: good twitter:image:alt flagged:\n%s", findings)
+	}
+}
+
+func TestAltRepeatsTitle(t *testing.T) {
+	groups := []imageGroup{{Property: "og:image", URL: "https://x/y.png", Alt: "Launch Day", HasAlt: true}}
+	if f := altTextFindings(groups, "launch  day"); len(f) != 1 {
+		t.Errorf("altTextFindings = %v, want title repetition", f)
+	}
+}
+
+func TestPixelHeuristics(t *testing.T) {
+	flat := image.NewGray(image.Rect(0, 0, 600, 315))
+	stripes := image.NewGray(image.Rect(0, 0, 600, 315))
+	for y := 0; y < 315; y++ {
+		for x := 0; x < 600; x++ {
+			flat.SetGray(x, y, color.Gray{uint8(120 + x%10)})
+			if (x/3)%2 == 0 {
+				stripes.SetGray(x, y, color.Gray{255})
+			}
+		}
+	}
+	if r := contrastRatio(flat); r >= 3 {
+		t.Errorf("contrastRatio(flat) = %.2f, want < 3", r)
+	}
+	if r := contrastRatio(stripes); r < 3 {
+		t.Errorf("contrastRatio(stripes) = %.2f, want ≥ 3", r)
+	}
+	if d := edgeDensity(flat); d > 0.12 {
+		t.Errorf("edgeDensity(flat) = %.2f, want low", d)
+	}
+	if d := edgeDensity(stripes); d <= 0.12 {
+		t.Errorf("edgeDensity(stripes) = %.2f, want high", d)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Article Fields
+// ------------------------------------------------------------------------------------------------
+
+// articleDateLayouts are the ISO 8601 forms accepted for article:*_time, most
+// specific first. Date-times without a zone are read as UTC; a bare date
+// needs none.
+var articleDateLayouts = []struct {
+	layout string
+	zoned  bool
+}{
+	{time.RFC3339Nano, true},
+	{"2006-01-02T15:04Z07:00", true},
+	{"2006-01-02T15:04:05.999999999Z0700", true},
+	{"2006-01-02T15:04Z0700", true},
+	{"2006-01-02T15:04:05.999999999", false},
+	{"2006-01-02T15:04", false},
+	{"2006-01-02", true},
+}
+
+// profileRefRe matches author references that are not URLs but still name a
+// profile: an @handle or a numeric Facebook profile ID.
+var profileRefRe = regexp.MustCompile(`^(?:@[A-Za-z0-9_.]{1,50}|[0-9]{5,20})$`)
+
+// parseArticleDate parses an ISO 8601 date or date-time and reports whether
+// it is unambiguous about its time zone.
+func parseArticleDate(s string) (t time.Time, zoned bool, ok bool) {
+	for _, l := range articleDateLayouts {
+		if parsed, err := time.Parse(l.layout, s); err == nil {
+			return parsed, l.zoned, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+// articleValues returns every value of article:<name>, including the legacy
+// og:article:<name> spelling.
+func articleValues(html, name string) []string {
+	return append(parseMetaAll(html, "article:"+name), parseMetaAll(html, "og:article:"+name)...)
+}
+
+// articleFindings checks the article:* fields of an og:type=article page:
+// ISO 8601 dates in a sensible order relative to each other and to now,
+// authors given as profile URLs and tags repeated rather than comma-joined.
+func articleFindings(html string, og map[string]string, now time.Time) []finding {
+	if strings.TrimSpace(og["type"]) != "article" {
+		return nil
+	}
+	var out []finding
+	dates := map[string]time.Time{}
+	for _, name := range []string{"published_time", "modified_time", "expiration_time"} {
+		vs := articleValues(html, name)
+		if len(vs) == 0 {
+			continue
+		}
+		v := strings.TrimSpace(vs[0])
+		t, zoned, ok := parseArticleDate(v)
+		switch {
+		case !ok:
+			out = append(out, warnf("article-date-format", "article:%s %q is not an ISO 8601 date (e.g. 2024-05-01T09:30:00Z)", name, v))
+			continue
+		case !zoned:
+			out = append(out, infof("article-date-timezone", "article:%s %q has no time zone; crawlers will assume UTC", name, v))
+		}
+		dates[name] = t
+	}
+
+	published, hasPublished := dates["published_time"]
+	if modified, ok := dates["modified_time"]; ok && hasPublished && modified.Before(published) {
+		out = append(out, warnf("article-modified-before-published", "article:modified_time %s is earlier than article:published_time %s", modified.Format(time.RFC3339), published.Format(time.RFC3339)))
+	}
+	if hasPublished && published.After(now) {
+		out = append(out, warnf("article-published-future", "article:published_time %s is in the future", published.Format(time.RFC3339)))
+	}
+	if expires, ok := dates["expiration_time"]; ok {
+		switch {
+		case !expires.After(now):
+			out = append(out, warnf("article-expired", "article:expiration_time %s has passed; platforms may stop showing the article", expires.Format(time.RFC3339)))
+		case hasPublished && expires.Before(published):
+			out = append(out, warnf("article-expires-before-published", "article:expiration_time %s is earlier than article:published_time %s", expires.Format(time.RFC3339), published.Format(time.RFC3339)))
+		}
+	}
+
+	for _, a := range articleValues(html, "author") {
+		a = strings.TrimSpace(a)
+		if a == "" || profileRefRe.MatchString(a) {
+			continue
+		}
+		if u, err := url.Parse(a); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			out = append(out, warnf("article-author-format", "article:author %q is not a profile URL; link to the author's page (og:type profile) and repeat the tag per author", a))
+		}
+	}
+
+	for _, tag := range articleValues(html, "tag") {
+		if strings.Contains(tag, ",") {
+			out = append(out, warnf("article-tag-joined", "article:tag %q joins several tags with commas; repeat article:tag once per tag", tag))
+		}
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseArticleDate(t *testing.T) {
+	for _, c := range []struct {
+		in        string
+		ok, zoned bool
+	}{
+		{"2024-05-01T09:30:00Z", true, true},
+		{"2024-05-01T09:30:00.123+02:00", true, true},
+		{"2024-05-01T09:30+0200", true, true},
+		{"2024-05-01T09:30:00", true, false},
+		{"2024-05-01", true, true},
+		{"01/05/2024", false, false},
+		{"May 1, 2024", false, false},
+	} {
+		_, zoned, ok := parseArticleDate(c.in)
+		if ok != c.ok || zoned != c.zoned {
+			t.Errorf("parseArticleDate(%q) = zoned %v, ok %v; want %v, %v", c.in, zoned, ok, c.zoned, c.ok)
+		}
+	}
+}
+
+func TestArticleFindings(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	og := map[string]string{"type": "article"}
+	html := `<html><head>
+		<meta property="article:published_time" content="2024-07-01T08:00:00Z">
+		<meta property="article:modified_time" content="2024-06-30T08:00:00Z">
+		<meta property="article:expiration_time" content="2024-05-01">
+		<meta property="article:author" content="https://example.com/authors/jane">
+		<meta property="article:author" content="@john">
+		<meta property="article:author" content="Jane Doe">
+		<meta property="article:tag" content="go, testing">
+		<meta property="article:tag" content="ogspy">
+	</head></html>`
+	got := ruleIDs(articleFindings(html, og, now))
+	want := "article-modified-before-published article-published-future article-expired article-author-format article-tag-joined"
+	if got != want {
+		t.Errorf("rules = %q, want %q", got, want)
+	}
+
+	html = `<meta property="og:article:published_time" content="yesterday">`
+	if got := ruleIDs(articleFindings(html, og, now)); got != "article-date-format" {
+		t.Errorf("legacy spelling: rules = %q", got)
+	}
+	if fs := articleFindings(html, map[string]string{"type": "website"}, now); len(fs) != 0 {
+		t.Errorf("non-article page: %v", messages(fs))
+	}
+
+	html = `<meta property="article:published_time" content="2024-05-01T10:00:00">`
+	if fs := articleFindings(html, og, now); len(fs) != 1 || fs[0].Severity != sevInfo || !strings.Contains(fs[0].Message, "UTC") {
+		t.Errorf("zone-less date: %+v", fs)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Canonical URL Consistency
+// ------------------------------------------------------------------------------------------------
+
+// urlReport lists the URLs that identify a page: the one requested, the one
+// reached after redirects, rel=canonical and og:url.
+type urlReport struct {
+	Requested string `json:"requested"`
+	Final     string `json:"final"`
+	Canonical string `json:"canonical,omitempty"`
+	OGURL     string `json:"og_url,omitempty"`
+}
+
+// trackingParams are query parameters that identify a campaign or click
+// rather than the content; "utm_" matches as a prefix.
+var trackingParams = []string{"utm_", "fbclid", "gclid", "dclid", "msclkid", "mc_cid", "mc_eid", "igshid", "_ga", "yclid"}
+
+// newURLReport collects the page URLs; rel=canonical is resolved against the
+// final URL.
+func newURLReport(requested string, p *page, og map[string]string) urlReport {
+	final := pick(p.FinalURL, requested)
+	return urlReport{
+		Requested: requested,
+		Final:     final,
+		Canonical: parseFallbacks(p.HTML, final).Canonical,
+		OGURL:     strings.TrimSpace(og["url"]),
+	}
+}
+
+// normalizeURL makes URLs comparable: lower-case scheme and host, no default
+// port, no fragment and "/" for an empty path. Unparsable input is returned
+// trimmed.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(raw)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment, u.RawFragment = "", ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// sameURL compares two URLs after normalisation.
+func sameURL(a, b string) bool { return normalizeURL(a) == normalizeURL(b) }
+
+// trackingFound returns the tracking parameters present in raw's query.
+func trackingFound(raw string) []string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	var found []string
+	for key := range u.Query() {
+		for _, p := range trackingParams {
+			if key == p || (strings.HasSuffix(p, "_") && strings.HasPrefix(key, p)) {
+				found = append(found, key)
+				break
+			}
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// canonicalFindings cross-checks the page URLs. With fetch set, an og:url
+// that differs from the final URL is requested to confirm it answers 200
+// without redirecting and declares itself as og:url.
+func canonicalFindings(ctx context.Context, r urlReport, fetch bool) []finding {
+	var out []finding
+	if !sameURL(r.Requested, r.Final) {
+		out = append(out, infof("url-redirect", "%s redirects to %s; share the final URL", r.Requested, r.Final))
+	}
+	if r.Canonical != "" && !sameURL(r.Canonical, r.Final) {
+		out = append(out, infof("canonical-elsewhere", "rel=canonical points to %s, not to the fetched page %s", r.Canonical, r.Final))
+	}
+	for _, u := range []struct{ label, raw string }{{"og:url", r.OGURL}, {"rel=canonical", r.Canonical}} {
+		if keys := trackingFound(u.raw); len(keys) > 0 {
+			out = append(out, warnf("url-tracking-params", "%s %s carries tracking parameters (%s); previews and share counts will split", u.label, u.raw, strings.Join(keys, ", ")))
+		}
+	}
+	if r.OGURL == "" {
+		return out
+	}
+	if u, err := url.Parse(r.OGURL); err != nil || !u.IsAbs() {
+		return append(out, warnf("og-url-relative", "og:url %q must be an absolute URL", r.OGURL))
+	}
+	if r.Canonical != "" && !sameURL(r.OGURL, r.Canonical) {
+		out = append(out, warnf("canonical-mismatch", "og:url %s differs from rel=canonical %s", r.OGURL, r.Canonical))
+	}
+	if !fetch || sameURL(r.OGURL, r.Final) {
+		return out
+	}
+
+	p, err := fetchPage(ctx, r.OGURL)
+	switch {
+	case err != nil:
+		return append(out, warnf("og-url-unreachable", "og:url %s cannot be fetched: %v", r.OGURL, err))
+	case !sameURL(p.FinalURL, r.OGURL):
+		out = append(out, warnf("og-url-redirect", "og:url %s redirects to %s; point og:url at the final URL", r.OGURL, p.FinalURL))
+	case p.Status != 200:
+		out = append(out, warnf("og-url-status", "og:url %s answers HTTP %d, not 200", r.OGURL, p.Status))
+	}
+	if target := strings.TrimSpace(parseOG(p.HTML)["url"]); target != "" && !sameURL(target, r.OGURL) {
+		out = append(out, warnf("og-url-not-self", "og:url %s is not self-referential: that page declares og:url %s", r.OGURL, target))
+	}
+	return out
+}
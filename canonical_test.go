@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	for _, c := range [][2]string{
+		{"HTTPS://Example.COM:443", "https://example.com/"},
+		{"http://example.com:80/a#top", "http://example.com/a"},
+		{"https://example.com:8443/a?b=1", "https://example.com:8443/a?b=1"},
+	} {
+		if got := normalizeURL(c[0]); got != c[1] {
+			t.Errorf("normalizeURL(%q) = %q, want %q", c[0], got, c[1])
+		}
+	}
+	if got := trackingFound("https://example.com/?utm_source=x&id=3&fbclid=y"); strings.Join(got, ",") != "fbclid,utm_source" {
+		t.Errorf("trackingFound = %v", got)
+	}
+}
+
+func TestCanonicalFindings(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/post", http.StatusMovedPermanently)
+		case "/post":
+			fmt.Fprintf(w, `<html><head><link rel="canonical" href="/post"><meta property="og:url" content="%s/post"></head></html>`, srv.URL)
+		case "/other":
+			fmt.Fprintf(w, `<html><head><meta property="og:url" content="%s/post"></head></html>`, srv.URL)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	p, err := fetchPage(ctx, srv.URL+"/old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.FinalURL != srv.URL+"/post" {
+		t.Fatalf("FinalURL = %q, want %s/post", p.FinalURL, srv.URL)
+	}
+	r := newURLReport(srv.URL+"/old", p, parseOG(p.HTML))
+	if r.Canonical != srv.URL+"/post" {
+		t.Errorf("Canonical = %q", r.Canonical)
+	}
+	got := ruleIDs(canonicalFindings(ctx, r, true))
+	if got != "url-redirect" {
+		t.Errorf("consistent page: rules = %q, want only url-redirect", got)
+	}
+
+	// og:url points at a redirecting, tracked URL and disagrees with rel=canonical.
+	r.OGURL = srv.URL + "/old?utm_source=feed"
+	got = ruleIDs(canonicalFindings(ctx, r, true))
+	for _, want := range []string{"url-tracking-params", "canonical-mismatch", "og-url-redirect"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rules %q lack %s", got, want)
+		}
+	}
+
+	// og:url resolves but that page names another URL.
+	r.OGURL = srv.URL + "/other"
+	if got = ruleIDs(canonicalFindings(ctx, r, true)); !strings.Contains(got, "og-url-not-self") {
+		t.Errorf("rules %q lack og-url-not-self", got)
+	}
+	r.OGURL = srv.URL + "/gone"
+	if got = ruleIDs(canonicalFindings(ctx, r, true)); !strings.Contains(got, "og-url-unreachable") {
+		t.Errorf("rules %q lack og-url-unreachable", got)
+	}
+	if got = ruleIDs(canonicalFindings(ctx, r, false)); strings.Contains(got, "og-url-unreachable") {
+		t.Errorf("fetched og:url without fetch: %q", got)
+	}
+}
+
+// ruleIDs joins the rule IDs of fs with spaces.
+func ruleIDs(fs []finding) string {
+	ids := make([]string, 0, len(fs))
+	for _, f := range fs {
+		ids = append(ids, f.Rule)
+	}
+	return strings.Join(ids, " ")
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------------
+// CI Report Formats
+// ------------------------------------------------------------------------------------------------
+
+// ciFormats are the machine-readable report formats of --format.
+var ciFormats = []string{"junit", "sarif", "tap", "github"}
+
+// ciResult is one URL in a CI report: every finding, and the subset that
+// makes the URL fail.
+type ciResult struct {
+	URL      string
+	Err      error
+	Findings []finding
+	Failed   []finding
+}
+
+// checkFormat validates a --format value; "text" is the coloured terminal
+// output.
+func checkFormat(format string) error {
+	if format == "text" {
+		return nil
+	}
+	for _, f := range ciFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown --format %q (want text, %s)", format, strings.Join(ciFormats, ", "))
+}
+
+// writeCIReportFile writes the report to path, or to STDOUT when path is empty.
+func writeCIReportFile(path, format, command string, rs []ciResult) error {
+	if path == "" {
+		return writeCIReport(os.Stdout, format, command, rs)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeCIReport(f, format, command, rs); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeCIReport renders rs in format; command names the test suite or run.
+func writeCIReport(w io.Writer, format, command string, rs []ciResult) error {
+	switch format {
+	case "junit":
+		return writeJUnit(w, command, rs)
+	case "sarif":
+		return writeSARIF(w, command, rs)
+	case "tap":
+		return writeTAP(w, rs)
+	case "github":
+		return writeGitHub(w, rs)
+	}
+	return checkFormat(format)
+}
+
+// --- JUnit XML --------------------------------------------------------------
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit maps each URL to a test case with a single <failure> listing
+// the failing findings (the schema allows one per test case), typed by the
+// rule ID when there is only one; findings below the threshold go to
+// <system-out>.
+func writeJUnit(w io.Writer, command string, rs []ciResult) error {
+	suite := junitSuite{Name: "ogspy " + command, Tests: len(rs)}
+	for _, r := range rs {
+		c := junitCase{Name: r.URL, Classname: "ogspy." + command}
+		if r.Err != nil {
+			c.Error = &junitProblem{Type: "fetch-error", Message: r.Err.Error()}
+			suite.Errors++
+		}
+		var out, failed, rules []string
+		for _, f := range r.Findings {
+			line := fmt.Sprintf("[%s] %s: %s", f.Rule, f.Severity, f.Message)
+			if slices.Contains(r.Failed, f) {
+				failed = append(failed, line)
+				if !slices.Contains(rules, f.Rule) {
+					rules = append(rules, f.Rule)
+				}
+				continue
+			}
+			out = append(out, line)
+		}
+		if len(failed) > 0 {
+			c.Failure = &junitProblem{Type: strings.Join(rules, ","), Message: fmt.Sprintf("%d failing finding(s): %s", len(failed), strings.Join(rules, ", ")), Text: strings.Join(failed, "\n")}
+			suite.Failures++
+		}
+		c.SystemOut = strings.Join(out, "\n")
+		suite.Cases = append(suite.Cases, c)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// --- SARIF 2.1.0 ------------------------------------------------------------
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool      sarifTool       `json:"tool"`
+	Artifacts []sarifArtifact `json:"artifacts"`
+	Results   []sarifResult   `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifArtifact struct {
+	Location sarifArtifactLocation `json:"location"`
+}
+
+type sarifArtifactLocation struct {
+	URI   string `json:"uri"`
+	Index int    `json:"index"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties struct {
+		Failed bool `json:"failed"` // at or above the exit threshold
+	} `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+// sarifLevel maps a finding severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case sevError:
+		return "error"
+	case sevWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+// writeSARIF maps each URL to an artifact and each finding to a result whose
+// level follows the severity; properties.failed marks the findings that fail
+// the URL.
+func writeSARIF(w io.Writer, command string, rs []ciResult) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "ogspy",
+			Version:        version,
+			InformationURI: "https://github.com/vincenzomaritato/ogspy",
+		}},
+		Artifacts: []sarifArtifact{},
+		Results:   []sarifResult{},
+	}
+	ids := map[string]bool{}
+	for i, r := range rs {
+		loc := sarifArtifactLocation{URI: r.URL, Index: i}
+		run.Artifacts = append(run.Artifacts, sarifArtifact{Location: loc})
+		add := func(rule, severity string, failed bool, msg string) {
+			res := sarifResult{RuleID: rule, Level: sarifLevel(severity), Message: sarifMessage{Text: msg}}
+			res.Properties.Failed = failed
+			var l sarifLocation
+			l.PhysicalLocation.ArtifactLocation = loc
+			res.Locations = []sarifLocation{l}
+			run.Results = append(run.Results, res)
+			ids[rule] = true
+		}
+		if r.Err != nil {
+			add("fetch-error", sevError, true, fmt.Sprintf("ogspy %s could not fetch %s: %v", command, r.URL, r.Err))
+		}
+		for _, f := range r.Findings {
+			add(f.Rule, f.Severity, slices.Contains(r.Failed, f), f.Message)
+		}
+	}
+	for id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id})
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool { return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID })
+	if run.Tool.Driver.Rules == nil {
+		run.Tool.Driver.Rules = []sarifRule{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// --- TAP 13 -----------------------------------------------------------------
+
+// writeTAP emits one test point per URL with its findings as a YAML block.
+func writeTAP(w io.Writer, rs []ciResult) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "TAP version 13\n1..%d\n", len(rs))
+	for i, r := range rs {
+		status := "ok"
+		if r.Err != nil || len(r.Failed) > 0 {
+			status = "not ok"
+		}
+		fmt.Fprintf(&b, "%s %d - %s\n", status, i+1, r.URL)
+		if r.Err == nil && len(r.Findings) == 0 {
+			continue
+		}
+		b.WriteString("  ---\n")
+		if r.Err != nil {
+			fmt.Fprintf(&b, "  error: %s\n", yamlQuote(r.Err.Error()))
+		}
+		if len(r.Findings) > 0 {
+			b.WriteString("  findings:\n")
+			for _, f := range r.Findings {
+				fmt.Fprintf(&b, "    - rule: %s\n      severity: %s\n      failed: %t\n      message: %s\n", yamlQuote(f.Rule), f.Severity, slices.Contains(r.Failed, f), yamlQuote(f.Message))
+			}
+		}
+		b.WriteString("  ...\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// yamlQuote renders s as a double-quoted YAML scalar, so that ":", "#" and
+// quotes in custom rule IDs and messages stay plain text; every escape
+// strconv.Quote emits is also a YAML escape.
+func yamlQuote(s string) string {
+	return strconv.Quote(s)
+}
+
+// --- GitHub Actions ---------------------------------------------------------
+
+// writeGitHub emits one workflow command per finding: ::error for failing
+// findings, ::warning and ::notice for the rest by severity.
+func writeGitHub(w io.Writer, rs []ciResult) error {
+	var b strings.Builder
+	for _, r := range rs {
+		if r.Err != nil {
+			fmt.Fprintf(&b, "::error title=%s::%s\n", ghProperty("ogspy [fetch-error]"), ghData(fmt.Sprintf("%s: %v", r.URL, r.Err)))
+		}
+		for _, f := range r.Findings {
+			cmd := "notice"
+			switch {
+			case slices.Contains(r.Failed, f):
+				cmd = "error"
+			case f.Severity != sevInfo:
+				cmd = "warning"
+			}
+			fmt.Fprintf(&b, "::%s title=%s::%s\n", cmd, ghProperty("ogspy ["+f.Rule+"]"), ghData(r.URL+": "+f.Message))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ghData escapes a workflow command message.
+func ghData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// ghProperty escapes a workflow command property value.
+func ghProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
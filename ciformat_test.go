@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func ciSample() []ciResult {
+	missing := finding{Rule: "og-image-missing", Severity: sevError, Message: "og:image is missing", Property: "og:image", Missing: true}
+	alt := warnf("alt-missing", "og:image has no og:image:alt text")
+	return []ciResult{
+		{URL: "https://a.example/", Findings: []finding{alt}},
+		{URL: "https://b.example/", Findings: []finding{missing, alt}, Failed: []finding{missing}},
+		{URL: "https://c.example/", Err: errors.New("connection refused")},
+	}
+}
+
+func TestJUnitReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCIReport(&buf, "junit", "validate", ciSample()); err != nil {
+		t.Fatal(err)
+	}
+	var got junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	s := got.Suites[0]
+	if s.Tests != 3 || s.Failures != 1 || s.Errors != 1 || len(s.Cases) != 3 {
+		t.Fatalf("suite = %+v", s)
+	}
+	if f := s.Cases[1].Failure; f == nil || f.Type != "og-image-missing" || f.Text != "[og-image-missing] error: og:image is missing" {
+		t.Errorf("failure = %+v", f)
+	}
+
+	// Several failing findings share the one <failure> of their test case.
+	rs := ciSample()
+	second := finding{Rule: "og-url-missing", Severity: sevError, Message: "og:url is missing"}
+	rs[1].Findings = append(rs[1].Findings, second)
+	rs[1].Failed = append(rs[1].Failed, second)
+	buf.Reset()
+	if err := writeCIReport(&buf, "junit", "validate", rs); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "<failure "); n != 1 {
+		t.Errorf("got %d <failure> elements, want 1:\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), `message="2 failing finding(s): og-image-missing, og-url-missing"`) {
+		t.Errorf("joined failure:\n%s", buf.String())
+	}
+	if !strings.Contains(s.Cases[0].SystemOut, "[alt-missing] warning") {
+		t.Errorf("system-out = %q", s.Cases[0].SystemOut)
+	}
+}
+
+func TestSARIFReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCIReport(&buf, "sarif", "validate", ciSample()); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	run := got.Runs[0]
+	if got.Version != "2.1.0" || len(run.Artifacts) != 3 || len(run.Results) != 4 || len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("run = %+v", run)
+	}
+	r := run.Results[1]
+	if r.RuleID != "og-image-missing" || r.Level != "error" || !r.Properties.Failed || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "https://b.example/" {
+		t.Errorf("result = %+v", r)
+	}
+	if r := run.Results[0]; r.Level != "warning" || r.Properties.Failed {
+		t.Errorf("non-failing result = %+v", r)
+	}
+}
+
+func TestTAPAndGitHubReports(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCIReport(&buf, "tap", "validate", ciSample()); err != nil {
+		t.Fatal(err)
+	}
+	tap := buf.String()
+	for _, want := range []string{"TAP version 13\n1..3\n", "ok 1 - https://a.example/\n", "not ok 2 - https://b.example/\n", "not ok 3 - https://c.example/\n", "error: \"connection refused\""} {
+		if !strings.Contains(tap, want) {
+			t.Errorf("TAP lacks %q:\n%s", want, tap)
+		}
+	}
+
+	// Custom rule IDs and messages with YAML syntax stay quoted scalars.
+	buf.Reset()
+	rs := ciSample()
+	rs[0].Findings[0] = finding{Rule: "team:og #1", Severity: sevInfo, Message: `say "hi": # not a comment`}
+	if err := writeCIReport(&buf, "tap", "validate", rs); err != nil {
+		t.Fatal(err)
+	}
+	if want := "    - rule: \"team:og #1\"\n      severity: info\n      failed: false\n      message: \"say \\\"hi\\\": # not a comment\"\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("TAP lacks %q:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	rs = ciSample()
+	rs[0].Findings[0].Message = "50% done\nnext line"
+	if err := writeCIReport(&buf, "github", "validate", rs); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{
+		"::warning title=ogspy [alt-missing]::https://a.example/: 50%25 done%0Anext line",
+		"::error title=ogspy [og-image-missing]::https://b.example/: og:image is missing",
+		"::warning title=ogspy [alt-missing]::https://b.example/: og:image has no og:image:alt text",
+		"::error title=ogspy [fetch-error]::https://c.example/: connection refused",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("github annotations:\n%s", buf.String())
+	}
+
+	if err := checkFormat("xml"); err == nil {
+		t.Error("checkFormat accepted xml")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Platform Crop Simulation
+// ------------------------------------------------------------------------------------------------
+
+// cropResult describes how one platform crop cuts the og:image.
+type cropResult struct {
+	Platform    string  `json:"platform"`
+	Ratio       float64 `json:"ratio"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	LossPercent float64 `json:"loss_percent"`
+	Trimmed     string  `json:"trimmed,omitempty"`
+	File        string  `json:"file,omitempty"`
+}
+
+// cropTarget is one crop to simulate.
+type cropTarget struct {
+	name  string
+	ratio float64
+}
+
+// cropTargets lists the large-card and thumbnail crops of every platform.
+func cropTargets() []cropTarget {
+	var targets []cropTarget
+	for _, p := range platforms {
+		targets = append(targets, cropTarget{p.Key, p.ImageRatio})
+		if p.ThumbRatio > 0 {
+			targets = append(targets, cropTarget{p.Key + "-thumb", p.ThumbRatio})
+		}
+	}
+	return targets
+}
+
+// centerCrop returns the largest rectangle of the given width/height ratio
+// centred in b, which is how every platform crops preview images.
+func centerCrop(b image.Rectangle, ratio float64) image.Rectangle {
+	w, h := b.Dx(), b.Dy()
+	if float64(w)/float64(h) > ratio {
+		cw := int(float64(h)*ratio + 0.5)
+		x0 := b.Min.X + (w-cw)/2
+		return image.Rect(x0, b.Min.Y, x0+cw, b.Max.Y)
+	}
+	ch := int(float64(w)/ratio + 0.5)
+	y0 := b.Min.Y + (h-ch)/2
+	return image.Rect(b.Min.X, y0, b.Max.X, y0+ch)
+}
+
+// describeCrop summarises what a crop removes from the original bounds.
+func describeCrop(b, c image.Rectangle) (loss float64, trimmed string) {
+	loss = 100 * (1 - float64(c.Dx()*c.Dy())/float64(b.Dx()*b.Dy()))
+	switch {
+	case c.Dx() < b.Dx():
+		trimmed = fmt.Sprintf("left/right %dpx each", (b.Dx()-c.Dx())/2)
+	case c.Dy() < b.Dy():
+		trimmed = fmt.Sprintf("top/bottom %dpx each", (b.Dy()-c.Dy())/2)
+	}
+	return loss, trimmed
+}
+
+// slugRe replaces everything that is unsafe in a file name.
+var slugRe = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// cropSlug derives a file-name prefix from the page URL.
+func cropSlug(pageURL string) string {
+	s := strings.TrimPrefix(strings.TrimPrefix(pageURL, "https://"), "http://")
+	s = strings.Trim(slugRe.ReplaceAllString(s, "-"), "-")
+	if len(s) > 80 {
+		s = s[:80]
+	}
+	return s
+}
+
+// writeCrops decodes the image behind imgURL and writes one PNG per crop
+// target into dir, named after the page URL and platform.
+func writeCrops(ctx context.Context, imgURL, pageURL, dir string) ([]cropResult, error) {
+	data, _, err := fetchImageBytes(ctx, imgURL)
+	if err != nil {
+		return nil, err
+	}
+	img, format, err := decodeImage(data)
+	if err != nil {
+		if errors.Is(err, errImageTooLarge) {
+			return nil, err
+		}
+		if info, sniffErr := sniffImage(data); sniffErr == nil {
+			return nil, fmt.Errorf("cannot crop og:image: %s decoding is not supported", info.Format)
+		}
+		return nil, fmt.Errorf("cannot decode og:image: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if logger != nil {
+		logger.Debug("image.crop", slog.String("url", imgURL), slog.String("format", format), slog.String("dir", dir))
+	}
+
+	b := img.Bounds()
+	slug := cropSlug(pageURL)
+	var results []cropResult
+	for _, t := range cropTargets() {
+		r := centerCrop(b, t.ratio)
+		res := cropResult{Platform: t.name, Ratio: t.ratio, Width: r.Dx(), Height: r.Dy()}
+		res.LossPercent, res.Trimmed = describeCrop(b, r)
+
+		cropped := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+		draw.Draw(cropped, cropped.Bounds(), img, r.Min, draw.Src)
+		res.File = filepath.Join(dir, slug+"-"+t.name+".png")
+		f, err := os.Create(res.File)
+		if err != nil {
+			return results, err
+		}
+		err = png.Encode(f, cropped)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
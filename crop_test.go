@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCenterCrop(t *testing.T) {
+	b := image.Rect(0, 0, 1200, 630)
+	if r := centerCrop(b, 1); r != image.Rect(285, 0, 915, 630) {
+		t.Errorf("square crop = %v", r)
+	}
+	if r := centerCrop(b, 2); r != image.Rect(0, 15, 1200, 615) {
+		t.Errorf("2:1 crop = %v", r)
+	}
+	loss, trimmed := describeCrop(b, centerCrop(b, 1))
+	if loss < 47 || loss > 48 || trimmed != "left/right 285px each" {
+		t.Errorf("describeCrop = %.1f%% %q", loss, trimmed)
+	}
+}
+
+func TestWriteCrops(t *testing.T) {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1200, 630)))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	crops, err := writeCrops(ctx, srv.URL+"/og.png", "https://example.com/post", dir)
+	if err != nil {
+		t.Fatalf("writeCrops error: %v", err)
+	}
+	if len(crops) != len(cropTargets()) {
+		t.Fatalf("got %d crops, want %d", len(crops), len(cropTargets()))
+	}
+	for _, c := range crops {
+		f, err := os.Open(c.File)
+		if err != nil {
+			t.Fatalf("crop %s not written: %v", c.Platform, err)
+		}
+		cfg, err := png.DecodeConfig(f)
+		f.Close()
+		if err != nil || cfg.Width != c.Width || cfg.Height != c.Height {
+			t.Errorf("crop %s: file is %dx%d, report says %dx%d", c.Platform, cfg.Width, cfg.Height, c.Width, c.Height)
+		}
+	}
+}
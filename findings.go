@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Findings, Severities & Suppressions
+// ------------------------------------------------------------------------------------------------
+
+// Severities, from most to least serious.
+const (
+	sevError   = "error"
+	sevWarning = "warning"
+	sevInfo    = "info"
+)
+
+// finding is one reported issue. Rule is a stable identifier that can be used
+// in --fail-on decisions, rules-file suppressions and ogspy-ignore comments.
+type finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Property string `json:"property,omitempty"` // set by declarative rules
+	Missing  bool   `json:"missing,omitempty"`  // the property is absent
+
+	recommended bool // from a rule that is neither essential nor scoped to og:type
+}
+
+// warnf builds a warning-severity finding.
+func warnf(rule, format string, args ...any) finding {
+	return finding{Rule: rule, Severity: sevWarning, Message: fmt.Sprintf(format, args...)}
+}
+
+// infof builds an info-severity finding.
+func infof(rule, format string, args ...any) finding {
+	return finding{Rule: rule, Severity: sevInfo, Message: fmt.Sprintf(format, args...)}
+}
+
+// messages returns the message of every finding.
+func messages(fs []finding) []string {
+	out := make([]string, 0, len(fs))
+	for _, f := range fs {
+		out = append(out, f.Message)
+	}
+	return out
+}
+
+// severityRank orders severities; higher is more serious.
+func severityRank(s string) int {
+	switch s {
+	case sevError:
+		return 3
+	case sevWarning:
+		return 2
+	case sevInfo:
+		return 1
+	}
+	return 0
+}
+
+// parseFailOn validates a --fail-on value and returns its rank; "none"
+// disables failing on findings altogether.
+func parseFailOn(s string) (int, error) {
+	if s == "none" {
+		return severityRank(sevError) + 1, nil
+	}
+	if r := severityRank(s); r > 0 {
+		return r, nil
+	}
+	return 0, fmt.Errorf("invalid --fail-on %q (want error, warning, info or none)", s)
+}
+
+// failing returns the findings at or above the threshold rank.
+func failing(fs []finding, threshold int) []finding {
+	var out []finding
+	for _, f := range fs {
+		if severityRank(f.Severity) >= threshold {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// ignoreRule suppresses a rule, optionally only on matching URLs.
+type ignoreRule struct {
+	Rule string   `json:"rule"`           // rule ID; a trailing "*" matches a prefix
+	URLs []string `json:"urls,omitempty"` // URL globs where "*" matches anything
+}
+
+// ignoreCommentRe matches <!-- ogspy-ignore rule-id [rule-id…] -->.
+var ignoreCommentRe = regexp.MustCompile(`<!--\s*ogspy-ignore\s+([^>]*?)\s*-->`)
+
+// inlineIgnores returns the rule IDs silenced by ogspy-ignore comments.
+func inlineIgnores(html string) []string {
+	var ids []string
+	for _, m := range ignoreCommentRe.FindAllStringSubmatch(html, -1) {
+		ids = append(ids, strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' })...)
+	}
+	return ids
+}
+
+// matchRuleID reports whether a suppression pattern covers id.
+func matchRuleID(pattern, id string) bool {
+	if p, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(id, p)
+	}
+	return pattern == id
+}
+
+// matchURLGlob matches raw against a glob in which "*" spans any characters,
+// slashes included.
+func matchURLGlob(glob, raw string) bool {
+	re := "^" + strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*") + "$"
+	ok, _ := regexp.MatchString(re, raw)
+	return ok
+}
+
+// suppress drops the findings silenced by the rules file for pageURL or by
+// ogspy-ignore comments in html, and reports how many were dropped.
+func (rs *ruleSet) suppress(pageURL, html string, fs []finding) ([]finding, int) {
+	inline := inlineIgnores(html)
+	ignored := func(id string) bool {
+		for _, p := range inline {
+			if matchRuleID(p, id) {
+				return true
+			}
+		}
+		for _, ig := range rs.Ignore {
+			if !matchRuleID(ig.Rule, id) {
+				continue
+			}
+			if len(ig.URLs) == 0 {
+				return true
+			}
+			for _, g := range ig.URLs {
+				if matchURLGlob(g, pageURL) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	kept := fs[:0:0]
+	for _, f := range fs {
+		if !ignored(f.Rule) {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(fs) - len(kept)
+}
+
+// hasRule reports whether any finding carries the rule ID.
+func hasRule(fs []finding, rule string) bool {
+	for _, f := range fs {
+		if f.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuppress(t *testing.T) {
+	rs, err := parseRules([]byte(`{"rules": [], "ignore": [
+		{"rule": "image-weight-*"},
+		{"rule": "og-locale-missing", "urls": ["https://example.com/legacy/*"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := []finding{
+		warnf("image-weight-whatsapp", "too heavy"),
+		{Rule: "og-locale-missing", Severity: sevError, Message: "og:locale is missing"},
+		warnf("alt-missing", "no alt"),
+		infof("link-redirect", "redirects"),
+	}
+	html := `<html><head><!-- ogspy-ignore alt-missing, link-* --></head></html>`
+
+	kept, n := rs.suppress("https://example.com/legacy/a/b", html, fs)
+	if n != 4 || len(kept) != 0 {
+		t.Errorf("legacy page: kept %v, suppressed %d", kept, n)
+	}
+	kept, n = rs.suppress("https://example.com/new", "<html></html>", fs)
+	if n != 1 || len(kept) != 3 || kept[0].Rule != "og-locale-missing" {
+		t.Errorf("new page: kept %v, suppressed %d", kept, n)
+	}
+	if len(fs) != 4 || fs[0].Rule != "image-weight-whatsapp" {
+		t.Errorf("suppress modified its input: %v", fs)
+	}
+}
+
+func TestFailOn(t *testing.T) {
+	fs := []finding{infof("a", "x"), warnf("b", "y")}
+	for _, c := range []struct {
+		level string
+		want  int
+	}{{"error", 0}, {"warning", 1}, {"info", 2}, {"none", 0}} {
+		threshold, err := parseFailOn(c.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(failing(fs, threshold)); got != c.want {
+			t.Errorf("--fail-on %s: %d failing, want %d", c.level, got, c.want)
+		}
+	}
+	if _, err := parseFailOn("fatal"); err == nil {
+		t.Error("parseFailOn(fatal) succeeded")
+	}
+}
+
+func TestRuleIDs(t *testing.T) {
+	fs := builtinRules.check(map[string]string{"og:type": "article"}, false, true)
+	var ids []string
+	for _, f := range fs {
+		ids = append(ids, f.Rule)
+	}
+	for _, want := range []string{"og-title-missing", "og-site_name-missing", "article-author-missing"} {
+		if !slices.Contains(ids, want) {
+			t.Errorf("rule IDs %v lack %q", ids, want)
+		}
+	}
+	if got := inlineIgnores("<!--ogspy-ignore a b-->\n<!-- ogspy-ignore c -->"); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("inlineIgnores = %v", got)
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Image Probing
+// ------------------------------------------------------------------------------------------------
+
+// imageInfo describes a probed og:image.
+type imageInfo struct {
+	Format string // "jpeg", "png", "gif", "webp", "avif"
+	MIME   string
+	Width  int
+	Height int
+	Size   int64 // bytes
+	imageTraits
+}
+
+// errSVG is returned for SVG images, which no major social platform renders.
+var errSVG = errors.New("og:image is an SVG, which Facebook, X, LinkedIn and Slack do not render; use PNG or JPEG")
+
+// errImageTooLarge is returned by decodeImage for images above maxDecodePixels.
+var errImageTooLarge = errors.New("og:image is too large to decode")
+
+const (
+	imageProbeBytes = 64 << 10   // enough for the header of virtually every image
+	maxImageBytes   = 5 << 20    // download cap for full image fetches
+	maxDecodePixels = 50_000_000 // pixel cap for full decodes
+)
+
+// decodeImage decodes data once its header declares no more than
+// maxDecodePixels: a few kilobytes of compressed data can otherwise expand
+// to gigabytes of pixels.
+func decodeImage(data []byte) (image.Image, string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if px := int64(cfg.Width) * int64(cfg.Height); px > maxDecodePixels {
+		return nil, "", fmt.Errorf("%w: %d×%d exceeds %d megapixels", errImageTooLarge, cfg.Width, cfg.Height, maxDecodePixels/1_000_000)
+	}
+	return image.Decode(bytes.NewReader(data))
+}
+
+// probeImage probes the image with a ranged GET that reads only the leading
+// bytes needed to sniff its format and dimensions. Servers without range
+// support, or images whose header spills past the probe window, fall back to a
+// bounded full download. The real byte size comes from Content-Range,
+// Content-Length or the full fetch.
+func probeImage(ctx context.Context, imgURL string) (*imageInfo, error) {
+	resp, err := getImage(ctx, imgURL, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { resp.Body.Close() }()
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, imageProbeBytes))
+	if err != nil {
+		return nil, err
+	}
+	size := imageSize(resp)
+	info, sniffErr := sniffImage(head)
+	if errors.Is(sniffErr, errSVG) {
+		return nil, sniffErr
+	}
+
+	if sniffErr != nil || size < 0 {
+		if resp.StatusCode == http.StatusPartialContent {
+			resp.Body.Close()
+			if resp, err = getImage(ctx, imgURL, false); err != nil {
+				return nil, err
+			}
+			head = nil
+		}
+		rest, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxImageBytes+1-len(head))))
+		if err != nil {
+			return nil, err
+		}
+		data := append(head, rest...)
+		if size < 0 {
+			size = int64(len(data))
+		}
+		if sniffErr != nil {
+			if info, sniffErr = sniffImage(data); sniffErr != nil {
+				return nil, sniffErr
+			}
+		}
+	}
+	info.Size = size
+	if logger != nil {
+		logger.Debug("image.probe",
+			slog.String("url", imgURL),
+			slog.Int("status", resp.StatusCode),
+			slog.String("format", info.Format),
+			slog.Int64("size", size),
+		)
+	}
+	return info, nil
+}
+
+// getImage issues the GET for probeImage, optionally limited to the probe
+// window through a Range header.
+func getImage(ctx context.Context, imgURL string, ranged bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imgURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "image/avif,image/webp,image/png,image/jpeg,image/*;q=0.8,*/*;q=0.5")
+	if ranged {
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", imageProbeBytes-1))
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch og:image: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		resp.Body.Close()
+		return nil, fmt.Errorf("og:image returned HTTP %d", resp.StatusCode)
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "image/svg") {
+		resp.Body.Close()
+		return nil, errSVG
+	}
+	return resp, nil
+}
+
+// imageSize returns the full size of the image behind resp, or -1 when the
+// server did not disclose it.
+func imageSize(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		// Content-Range: bytes 0-65535/123456
+		cr := resp.Header.Get("Content-Range")
+		if i := strings.LastIndexByte(cr, '/'); i >= 0 {
+			if n, err := strconv.ParseInt(cr[i+1:], 10, 64); err == nil {
+				return n
+			}
+		}
+		return -1
+	}
+	return resp.ContentLength
+}
+
+// fetchImageBytes downloads a whole image (up to 5 MB) and returns it with
+// its Content-Type.
+func fetchImageBytes(ctx context.Context, imgURL string) ([]byte, string, error) {
+	resp, err := getImage(ctx, imgURL, false)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// imageWarnings checks resolution and aspect ratio against the 1200×630
+// (1.91:1) size recommended by every major platform. Byte size is checked per
+// platform by imageAdvice.
+func imageWarnings(info *imageInfo) []finding {
+	var warns []finding
+	if info.Width < 1200 || info.Height < 630 {
+		warns = append(warns, warnf("image-resolution", "og:image resolution too small (%dx%d)", info.Width, info.Height))
+	}
+	ratio := float64(info.Width) / float64(info.Height)
+	if math.Abs(ratio-1.91) > 0.1 {
+		warns = append(warns, warnf("image-ratio", "og:image aspect ratio %.2f deviates from 1.91:1", ratio))
+	}
+	return warns
+}
+
+// declaredImageWarnings cross-checks og:image:type, og:image:width and
+// og:image:height against what the image actually is.
+func declaredImageWarnings(info *imageInfo, og map[string]string) []finding {
+	var warns []finding
+	if t := strings.ToLower(strings.TrimSpace(og["image:type"])); t != "" && t != info.MIME {
+		if !(t == "image/jpg" && info.MIME == "image/jpeg") {
+			warns = append(warns, warnf("image-type-mismatch", "og:image:type declares %s but the image is %s", t, info.MIME))
+		}
+	}
+	for _, dim := range []struct {
+		key    string
+		actual int
+	}{{"image:width", info.Width}, {"image:height", info.Height}} {
+		v := strings.TrimSpace(og[dim.key])
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		switch {
+		case err != nil || n <= 0:
+			warns = append(warns, warnf("image-dimensions-invalid", "og:%s %q is not a positive integer", dim.key, v))
+		case n != dim.actual:
+			warns = append(warns, warnf("image-dimensions-mismatch", "og:%s declares %d but the image is %d px", dim.key, n, dim.actual))
+		}
+	}
+	return warns
+}
+
+// ------------------------------------------------------------------------------------------------
+// Format Sniffing
+// ------------------------------------------------------------------------------------------------
+
+// sniffImage identifies the image format from its leading bytes and returns
+// its dimensions. JPEG, PNG and GIF go through the standard decoders; WebP
+// and AVIF headers are parsed directly.
+func sniffImage(data []byte) (*imageInfo, error) {
+	switch {
+	case isSVG(data):
+		return nil, errSVG
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return sniffWebP(data)
+	case len(data) >= 12 && string(data[4:8]) == "ftyp":
+		return sniffAVIF(data)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode og:image: %w", err)
+	}
+	info := &imageInfo{Format: format, MIME: "image/" + format, Width: cfg.Width, Height: cfg.Height}
+	readImageTraits(info, data)
+	return info, nil
+}
+
+// isSVG reports whether data looks like an SVG document.
+func isSVG(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	s := strings.ToLower(strings.TrimSpace(string(head)))
+	return strings.HasPrefix(s, "<svg") || (strings.HasPrefix(s, "<?xml") || strings.HasPrefix(s, "<!doctype svg")) && strings.Contains(s, "<svg")
+}
+
+// sniffWebP reads the canvas size from a VP8, VP8L or VP8X chunk header.
+func sniffWebP(data []byte) (*imageInfo, error) {
+	info := &imageInfo{Format: "webp", MIME: "image/webp"}
+	if len(data) < 30 {
+		return nil, errors.New("cannot decode og:image: truncated WebP header")
+	}
+	switch string(data[12:16]) {
+	case "VP8 ":
+		if data[23] != 0x9d || data[24] != 0x01 || data[25] != 0x2a {
+			return nil, errors.New("cannot decode og:image: bad VP8 start code")
+		}
+		info.Width = int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff)
+		info.Height = int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff)
+	case "VP8L":
+		if data[20] != 0x2f {
+			return nil, errors.New("cannot decode og:image: bad VP8L signature")
+		}
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		info.Width = int(bits&0x3fff) + 1
+		info.Height = int((bits>>14)&0x3fff) + 1
+	case "VP8X":
+		info.Width = int(uint32(data[24])|uint32(data[25])<<8|uint32(data[26])<<16) + 1
+		info.Height = int(uint32(data[27])|uint32(data[28])<<8|uint32(data[29])<<16) + 1
+	default:
+		return nil, fmt.Errorf("cannot decode og:image: unknown WebP chunk %q", data[12:16])
+	}
+	readImageTraits(info, data)
+	return info, nil
+}
+
+// sniffAVIF reads the dimensions from the ISO-BMFF "ispe" property. Files
+// may carry several (thumbnails, alpha planes); the largest is the primary.
+func sniffAVIF(data []byte) (*imageInfo, error) {
+	brands := string(data[8:min(len(data), 64)])
+	if !strings.Contains(brands, "avif") && !strings.Contains(brands, "avis") {
+		return nil, fmt.Errorf("cannot decode og:image: unsupported ISO-BMFF brand %q", data[8:12])
+	}
+	info := &imageInfo{Format: "avif", MIME: "image/avif"}
+	for off := 0; ; {
+		i := bytes.Index(data[off:], []byte("ispe"))
+		if i < 0 {
+			break
+		}
+		p := off + i + 4 + 4 // skip box type and version/flags
+		if p+8 > len(data) {
+			break
+		}
+		w := int(binary.BigEndian.Uint32(data[p:]))
+		h := int(binary.BigEndian.Uint32(data[p+4:]))
+		if w*h > info.Width*info.Height {
+			info.Width, info.Height = w, h
+		}
+		off = p
+	}
+	if info.Width == 0 {
+		return nil, errors.New("cannot decode og:image: AVIF without ispe property")
+	}
+	return info, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// webpVP8X builds a minimal extended WebP header for a w×h canvas.
+func webpVP8X(w, h int) []byte {
+	b := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x00\x00\x00\x00")
+	for _, v := range []int{w - 1, h - 1} {
+		b = append(b, byte(v), byte(v>>8), byte(v>>16))
+	}
+	return b
+}
+
+// avifHeader builds a minimal ftyp box followed by an ispe property.
+func avifHeader(w, h int) []byte {
+	b := []byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00avifmif1miaf")
+	b = append(b, []byte("\x00\x00\x00\x14ispe\x00\x00\x00\x00")...)
+	b = binary.BigEndian.AppendUint32(b, uint32(w))
+	return binary.BigEndian.AppendUint32(b, uint32(h))
+}
+
+func TestSniffImage(t *testing.T) {
+	var pngBuf, jpgBuf bytes.Buffer
+	_ = png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 40, 21)))
+	_ = jpeg.Encode(&jpgBuf, image.NewRGBA(image.Rect(0, 0, 1200, 630)), nil)
+
+	cases := []struct {
+		name   string
+		data   []byte
+		format string
+		w, h   int
+	}{
+		{"png", pngBuf.Bytes(), "png", 40, 21},
+		{"jpeg", jpgBuf.Bytes(), "jpeg", 1200, 630},
+		{"webp", webpVP8X(1200, 630), "webp", 1200, 630},
+		{"avif", avifHeader(1920, 1005), "avif", 1920, 1005},
+	}
+	for _, c := range cases {
+		info, err := sniffImage(c.data)
+		if err != nil {
+			t.Errorf("%s: sniffImage error: %v", c.name, err)
+			continue
+		}
+		if info.Format != c.format || info.Width != c.w || info.Height != c.h {
+			t.Errorf("%s: got %s %dx%d, want %s %dx%d", c.name, info.Format, info.Width, info.Height, c.format, c.w, c.h)
+		}
+	}
+
+	svg := []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+	if _, err := sniffImage(svg); !errors.Is(err, errSVG) {
+		t.Errorf("sniffImage(svg) error = %v, want errSVG", err)
+	}
+}
+
+func TestDeclaredImageWarnings(t *testing.T) {
+	info := &imageInfo{Format: "png", MIME: "image/png", Width: 1200, Height: 630}
+	og := map[string]string{"image:type": "image/jpeg", "image:width": "1200", "image:height": "600"}
+	if w := declaredImageWarnings(info, og); len(w) != 2 {
+		t.Errorf("declaredImageWarnings = %v, want type and height mismatches", w)
+	}
+	og = map[string]string{"image:type": "image/png", "image:width": "1200", "image:height": "630"}
+	if w := declaredImageWarnings(info, og); len(w) != 0 {
+		t.Errorf("declaredImageWarnings: unexpected %v", w)
+	}
+}
+
+func TestProbeImageRange(t *testing.T) {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1200, 630)))
+	// Pad past the probe window so a full download would be noticeable.
+	body := append(buf.Bytes(), make([]byte, imageProbeBytes*2)...)
+
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if !strings.HasPrefix(r.Header.Get("User-Agent"), "OGSPY/") {
+			t.Errorf("missing User-Agent, got %q", r.Header.Get("User-Agent"))
+		}
+		if r.URL.Path == "/norange.png" {
+			w.Header().Set("Content-Type", "image/png")
+			w.(http.Flusher).Flush() // chunked: no Content-Length
+			_, _ = w.Write(body)
+			return
+		}
+		http.ServeContent(w, r, "og.png", time.Time{}, bytes.NewReader(body))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	info, err := probeImage(ctx, srv.URL+"/og.png")
+	if err != nil {
+		t.Fatalf("probeImage error: %v", err)
+	}
+	if info.Width != 1200 || info.Height != 630 || info.Size != int64(len(body)) {
+		t.Errorf("probeImage = %dx%d %d bytes, want 1200x630 %d bytes", info.Width, info.Height, info.Size, len(body))
+	}
+	if len(ranges) != 1 || ranges[0] == "" {
+		t.Errorf("expected a single ranged request, got %q", ranges)
+	}
+
+	info, err = probeImage(ctx, srv.URL+"/norange.png")
+	if err != nil {
+		t.Fatalf("probeImage(no range) error: %v", err)
+	}
+	if info.Size != int64(len(body)) {
+		t.Errorf("probeImage(no range) size = %d, want %d", info.Size, len(body))
+	}
+}
+
+// pngWithSize returns a 1×1 PNG whose IHDR declares w×h.
+func pngWithSize(w, h int) []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1)))
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b[16:], uint32(w))
+	binary.BigEndian.PutUint32(b[20:], uint32(h))
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+	return b
+}
+
+func TestDecodeImageLimit(t *testing.T) {
+	if _, _, err := decodeImage(pngWithSize(1, 1)); err != nil {
+		t.Errorf("decodeImage(1×1) = %v", err)
+	}
+	if _, _, err := decodeImage(pngWithSize(20000, 20000)); !errors.Is(err, errImageTooLarge) {
+		t.Errorf("decodeImage(20000×20000) = %v, want errImageTooLarge", err)
+	}
+}
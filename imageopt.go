@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Image Weight & Format Advice
+// ------------------------------------------------------------------------------------------------
+
+// imageTraits are encoder details read from the image header that matter to
+// crawlers: they do not honour EXIF orientation, render only the first frame
+// of an animation and mangle CMYK colours.
+type imageTraits struct {
+	Progressive  bool // progressive JPEG
+	CMYK         bool // 4-component JPEG
+	Orientation  int  // EXIF orientation (0 = absent, 1 = upright)
+	Animated     bool // animated GIF or WebP
+	PNGTrueColor bool // PNG colour type 2 or 6 (no palette)
+}
+
+// readImageTraits fills the traits of info from the leading bytes of the file.
+func readImageTraits(info *imageInfo, data []byte) {
+	switch info.Format {
+	case "jpeg":
+		readJPEGTraits(&info.imageTraits, data)
+	case "png":
+		// IHDR colour type sits at byte 25.
+		info.PNGTrueColor = len(data) > 25 && (data[25] == 2 || data[25] == 6)
+	case "gif":
+		info.Animated = gifFrames(data) > 1 || bytes.Contains(data, []byte("NETSCAPE2.0"))
+	case "webp":
+		info.Animated = len(data) > 20 && string(data[12:16]) == "VP8X" && data[20]&0x02 != 0
+	}
+}
+
+// readJPEGTraits walks the JPEG marker segments up to the first frame header.
+func readJPEGTraits(t *imageTraits, data []byte) {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return
+		}
+		marker := data[i+1]
+		if marker == 0xFF { // fill byte
+			i++
+			continue
+		}
+		if marker == 0x01 || marker >= 0xD0 && marker <= 0xD7 { // TEM, RSTn: no length
+			i += 2
+			continue
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 {
+			return
+		}
+		// The probe may hold only the head of the file: a segment running
+		// past the data is read as far as it goes and ends the walk.
+		seg := data[i+4 : min(len(data), i+2+length)]
+		switch {
+		case marker == 0xE1 && bytes.HasPrefix(seg, []byte("Exif\x00\x00")):
+			t.Orientation = exifOrientation(seg[6:])
+		case marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC:
+			// SOFn: precision(1) height(2) width(2) components(1)
+			t.Progressive = marker == 0xC2 || marker == 0xC6 || marker == 0xCA || marker == 0xCE
+			if len(seg) > 5 {
+				t.CMYK = seg[5] == 4
+			}
+			return
+		}
+		i += 2 + length
+	}
+}
+
+// exifOrientation reads tag 0x0112 from IFD0 of a TIFF-structured EXIF block.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	n := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < n; e++ {
+		off := ifd + 2 + e*12
+		if off+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[off:]) == 0x0112 {
+			return int(order.Uint16(tiff[off+8:]))
+		}
+	}
+	return 0
+}
+
+// gifFrames counts the image descriptors found in the available GIF bytes.
+func gifFrames(data []byte) int {
+	if len(data) < 13 {
+		return 0
+	}
+	i := 13
+	if data[10]&0x80 != 0 { // global colour table
+		i += 3 << (int(data[10]&0x07) + 1)
+	}
+	skipSubBlocks := func(i int) int {
+		for i < len(data) && data[i] != 0 {
+			i += int(data[i]) + 1
+		}
+		return i + 1
+	}
+	frames := 0
+	for i < len(data) {
+		switch data[i] {
+		case 0x21: // extension
+			i = skipSubBlocks(i + 2)
+		case 0x2C: // image descriptor
+			frames++
+			if i+10 > len(data) {
+				return frames
+			}
+			flags := data[i+9]
+			i += 10
+			if flags&0x80 != 0 { // local colour table
+				i += 3 << (int(flags&0x07) + 1)
+			}
+			i = skipSubBlocks(i + 1) // LZW minimum code size, then data
+		default: // trailer or garbage
+			return frames
+		}
+	}
+	return frames
+}
+
+// imageAdvice turns size budgets and encoder traits into findings, each with a
+// concrete fix.
+func imageAdvice(info *imageInfo) []finding {
+	var out []finding
+	kb := func(n int64) string {
+		if n >= 1<<20 {
+			return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+		}
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+
+	if info.Size > 0 {
+		for _, p := range platforms {
+			if p.MaxImageBytes > 0 && info.Size > p.MaxImageBytes {
+				out = append(out, warnf("image-weight-"+p.Key, "og:image is %s; %s drops images above %s → resize to 1200×630 and re-encode (JPEG quality ~80 or WebP) to stay under %s",
+					kb(info.Size), p.Name, kb(p.MaxImageBytes), kb(p.MaxImageBytes)))
+			}
+		}
+	}
+	if info.Format == "jpeg" && !info.Progressive && info.Size > 100<<10 {
+		out = append(out, infof("image-progressive", "og:image is a %s baseline JPEG → re-encode as progressive (e.g. `jpegtran -progressive -optimize -copy none in.jpg > out.jpg`)", kb(info.Size)))
+	}
+	if info.Format == "jpeg" && info.CMYK {
+		out = append(out, warnf("image-cmyk", "og:image is a CMYK JPEG; crawlers render it with wrong colours or not at all → convert to sRGB (e.g. `magick in.jpg -colorspace sRGB out.jpg`)"))
+	}
+	if info.Orientation > 1 {
+		out = append(out, warnf("image-exif-orientation", "og:image relies on EXIF orientation %d, which crawlers ignore, so the preview appears rotated → bake the rotation into the pixels (e.g. `magick in.jpg -auto-orient -strip out.jpg`)", info.Orientation))
+	}
+	if info.Format == "png" && info.PNGTrueColor && info.Size > 200<<10 && info.Width*info.Height > 0 &&
+		float64(info.Size)/float64(info.Width*info.Height) > 0.5 {
+		out = append(out, infof("image-png-photo", "og:image is a %s true-colour PNG that looks like a photo → JPEG or WebP would be several times smaller", kb(info.Size)))
+	}
+	if info.Animated {
+		out = append(out, warnf("image-animated", "og:image is an animated %s; platforms show only the first frame → export a static image with the key frame", info.Format))
+	}
+	return out
+}
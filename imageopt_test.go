@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"strings"
+	"testing"
+)
+
+// withExif inserts an APP1 Exif segment carrying orientation o after SOI.
+func withExif(jpg []byte, o byte) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00")
+	tiff = append(tiff, o, 0, 0, 0, 0, 0, 0)
+	seg := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := append([]byte{0xFF, 0xE1, byte((len(seg) + 2) >> 8), byte(len(seg) + 2)}, seg...)
+	return append(append(append([]byte{}, jpg[:2]...), app1...), jpg[2:]...)
+}
+
+func TestReadImageTraits(t *testing.T) {
+	var jpg bytes.Buffer
+	_ = jpeg.Encode(&jpg, image.NewRGBA(image.Rect(0, 0, 64, 64)), nil)
+
+	info, err := sniffImage(withExif(jpg.Bytes(), 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Progressive || info.CMYK || info.Orientation != 6 {
+		t.Errorf("jpeg traits = %+v, want baseline RGB with orientation 6", info.imageTraits)
+	}
+
+	// Progressive SOF2 with four components.
+	var tr imageTraits
+	readJPEGTraits(&tr, []byte{0xFF, 0xD8, 0xFF, 0xC2, 0x00, 0x14, 0x08, 0x00, 0x10, 0x00, 0x10, 0x04})
+	if !tr.Progressive || !tr.CMYK {
+		t.Errorf("SOF2 traits = %+v, want progressive CMYK", tr)
+	}
+
+	// A stray RST marker followed by zero bytes, and a segment whose length is
+	// below 2, end the walk instead of slicing out of range.
+	for _, data := range [][]byte{
+		{0xFF, 0xD8, 0xFF, 0xD0, 0x00, 0x00},
+		{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x00, 0xFF, 0xC2},
+		{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x40, 0x00, 0x00},
+	} {
+		tr = imageTraits{}
+		readJPEGTraits(&tr, data)
+		if tr != (imageTraits{}) {
+			t.Errorf("readJPEGTraits(% x) = %+v, want no traits", data, tr)
+		}
+	}
+
+	pal := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{
+		Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 8, 8), pal), image.NewPaletted(image.Rect(0, 0, 8, 8), pal)},
+		Delay: []int{10, 10},
+	}
+	var g bytes.Buffer
+	_ = gif.EncodeAll(&g, anim)
+	if info, err := sniffImage(g.Bytes()); err != nil || !info.Animated {
+		t.Errorf("animated gif: info=%+v err=%v", info, err)
+	}
+	g.Reset()
+	_ = gif.Encode(&g, anim.Image[0], nil)
+	if info, err := sniffImage(g.Bytes()); err != nil || info.Animated {
+		t.Errorf("static gif: info=%+v err=%v", info, err)
+	}
+
+	webp := webpVP8X(1200, 630)
+	webp[20] |= 0x02
+	if info, err := sniffImage(webp); err != nil || !info.Animated {
+		t.Errorf("animated webp: info=%+v err=%v", info, err)
+	}
+}
+
+func TestImageAdvice(t *testing.T) {
+	info := &imageInfo{Format: "jpeg", Width: 1200, Height: 630, Size: 600 << 10,
+		imageTraits: imageTraits{CMYK: true, Orientation: 8}}
+	advice := strings.Join(messages(imageAdvice(info)), "\n")
+	for _, want := range []string{"WhatsApp drops images above 300 KB", "progressive", "CMYK", "EXIF orientation 8"} {
+		if !strings.Contains(advice, want) {
+			t.Errorf("imageAdvice missing %q:\n%s", want, advice)
+		}
+	}
+	if strings.Contains(advice, "Facebook") {
+		t.Errorf("600 KB should fit Facebook's budget:\n%s", advice)
+	}
+
+	photo := &imageInfo{Format: "png", Width: 1200, Height: 630, Size: 1 << 20, imageTraits: imageTraits{PNGTrueColor: true}}
+	if a := strings.Join(messages(imageAdvice(photo)), "\n"); !strings.Contains(a, "looks like a photo") {
+		t.Errorf("photo PNG advice = %q", a)
+	}
+	small := &imageInfo{Format: "png", Width: 1200, Height: 630, Size: 80 << 10, imageTraits: imageTraits{PNGTrueColor: true}}
+	if a := imageAdvice(small); len(a) != 0 {
+		t.Errorf("small PNG advice = %v, want none", a)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Link Checking
+// ------------------------------------------------------------------------------------------------
+
+// linkPrefixes are the metadata namespaces whose URL values get link-checked.
+var linkPrefixes = []string{"og:", "article:", "al:", "book:", "profile:", "music:", "video:"}
+
+// maxLinkRedirects bounds the redirect chain followed per link.
+const maxLinkRedirects = 10
+
+// linkTarget is one URL-valued property occurrence; repeated properties such
+// as og:see_also yield one target each.
+type linkTarget struct {
+	Property string
+	URL      string
+}
+
+// linkResult is the outcome of checking one linkTarget.
+type linkResult struct {
+	Property  string   `json:"property"`
+	URL       string   `json:"url"`
+	Status    int      `json:"status,omitempty"`
+	Redirects []string `json:"redirects,omitempty"`
+	FinalURL  string   `json:"final_url,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	TLSError  bool     `json:"tls_error,omitempty"`
+}
+
+// Dead reports whether the link is unusable for a crawler.
+func (r linkResult) Dead() bool {
+	return r.Error != "" || r.Status >= http.StatusBadRequest
+}
+
+// linkTargets collects every absolute http(s) URL found in the content of an
+// og:*, article:*, al:*, object-type or custom-namespace meta tag, in
+// document order.
+func linkTargets(html string) []linkTarget {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	prefixes := slices.Clone(linkPrefixes)
+	for _, p := range customPrefixes(parseNamespaces(html)) {
+		prefixes = append(prefixes, p+":")
+	}
+	var targets []linkTarget
+	seen := make(map[linkTarget]bool)
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		prop, ok := s.Attr("property")
+		if !ok {
+			prop, _ = s.Attr("name")
+		}
+		content, _ := s.Attr("content")
+		if !hasAnyPrefix(prop, prefixes) {
+			return
+		}
+		u, err := url.Parse(strings.TrimSpace(content))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return
+		}
+		t := linkTarget{Property: prop, URL: u.String()}
+		if !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+	})
+	return targets
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLinks checks every target with at most workers requests in flight and
+// returns the results in the order of targets.
+func checkLinks(ctx context.Context, targets []linkTarget, workers int) []linkResult {
+	results := make([]linkResult, len(targets))
+	if workers <= 0 {
+		workers = 4
+	}
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(workers, len(targets)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				results[i] = checkLink(ctx, targets[i])
+			}
+		}()
+	}
+	for i := range targets {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+	return results
+}
+
+// checkLink follows the redirect chain of one target by hand so every hop is
+// recorded. HEAD is tried first; servers that reject it get a GET.
+func checkLink(ctx context.Context, t linkTarget) linkResult {
+	res := linkResult{Property: t.Property, URL: t.URL}
+	client := *httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	current := t.URL
+	for hop := 0; ; hop++ {
+		resp, err := linkRequest(ctx, &client, http.MethodHead, current)
+		if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+			resp.Body.Close()
+			resp, err = linkRequest(ctx, &client, http.MethodGet, current)
+		}
+		if err != nil {
+			res.Error = err.Error()
+			res.TLSError = isTLSError(err)
+			return res
+		}
+		resp.Body.Close()
+		res.Status = resp.StatusCode
+
+		loc := resp.Header.Get("Location")
+		if resp.StatusCode < 300 || resp.StatusCode >= 400 || loc == "" {
+			break
+		}
+		if hop == maxLinkRedirects {
+			res.Error = fmt.Sprintf("stopped after %d redirects", maxLinkRedirects)
+			return res
+		}
+		next, err := resp.Request.URL.Parse(loc)
+		if err != nil {
+			res.Error = fmt.Sprintf("invalid redirect Location %q", loc)
+			return res
+		}
+		current = next.String()
+		res.Redirects = append(res.Redirects, current)
+	}
+	if len(res.Redirects) > 0 {
+		res.FinalURL = current
+	}
+	return res
+}
+
+// linkRequest performs a single, non-following request for checkLink.
+func linkRequest(ctx context.Context, client *http.Client, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+	return client.Do(req)
+}
+
+// isTLSError reports whether err stems from certificate verification or the
+// TLS handshake.
+func isTLSError(err error) bool {
+	var (
+		verr  *tls.CertificateVerificationError
+		rerr  tls.RecordHeaderError
+		aerr  tls.AlertError
+		uaerr x509.UnknownAuthorityError
+		herr  x509.HostnameError
+		cerr  x509.CertificateInvalidError
+	)
+	return errors.As(err, &verr) || errors.As(err, &rerr) || errors.As(err, &aerr) ||
+		errors.As(err, &uaerr) || errors.As(err, &herr) || errors.As(err, &cerr)
+}
+
+// linkWarnings turns link results into human-readable findings.
+func linkWarnings(results []linkResult) []finding {
+	var warns []finding
+	for _, r := range results {
+		switch {
+		case r.TLSError:
+			warns = append(warns, warnf("link-tls", "%s %s has a TLS error: %s", r.Property, r.URL, r.Error))
+		case r.Error != "":
+			warns = append(warns, warnf("link-dead", "%s %s is dead: %s", r.Property, r.URL, r.Error))
+		case r.Status >= http.StatusBadRequest:
+			warns = append(warns, warnf("link-dead", "%s %s is dead (HTTP %d)", r.Property, r.URL, r.Status))
+		case r.FinalURL != "":
+			warns = append(warns, infof("link-redirect", "%s %s redirects to %s (%d hop(s))", r.Property, r.URL, r.FinalURL, len(r.Redirects)))
+		}
+	}
+	return warns
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLinkTargets(t *testing.T) {
+	html := `<html><head>
+	<meta property="og:url" content="https://example.com/a">
+	<meta property="og:see_also" content="https://example.com/b">
+	<meta property="og:see_also" content="https://example.com/c">
+	<meta property="article:author" content="https://example.com/authors/jane">
+	<meta property="al:ios:url" content="example://open/a">
+	<meta property="al:web:url" content="https://example.com/a">
+	<meta property="og:title" content="Not a URL">
+	</head></html>`
+	got := linkTargets(html)
+	if len(got) != 5 {
+		t.Fatalf("linkTargets = %v, want 5 http(s) targets", got)
+	}
+	if got[1].Property != "og:see_also" || got[2].Property != "og:see_also" {
+		t.Errorf("repeated og:see_also values must each be checked, got %v", got)
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/old":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/head-hater":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	res := checkLinks(ctx, []linkTarget{
+		{"og:url", srv.URL + "/ok"},
+		{"og:see_also", srv.URL + "/old"},
+		{"og:see_also", srv.URL + "/gone"},
+		{"al:web:url", srv.URL + "/head-hater"},
+	}, 2)
+
+	if res[0].Status != 200 || res[0].Dead() {
+		t.Errorf("ok link: %+v", res[0])
+	}
+	if res[1].FinalURL != srv.URL+"/ok" || len(res[1].Redirects) != 1 || res[1].Status != 200 {
+		t.Errorf("redirect link: %+v", res[1])
+	}
+	if !res[2].Dead() || res[2].Status != 404 {
+		t.Errorf("dead link: %+v", res[2])
+	}
+	if res[3].Status != 200 {
+		t.Errorf("HEAD fallback link: %+v", res[3])
+	}
+	warns := strings.Join(messages(linkWarnings(res)), "\n")
+	if !strings.Contains(warns, "is dead (HTTP 404)") || !strings.Contains(warns, "redirects to") {
+		t.Errorf("linkWarnings:\n%s", warns)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Locale & Alternate Languages
+// ------------------------------------------------------------------------------------------------
+
+// ogLocaleRe is the ll_TT form Facebook expects: an ISO 639 language and an
+// ISO 3166 (or UN M.49) territory.
+var ogLocaleRe = regexp.MustCompile(`^[a-z]{2,3}_(?:[A-Z]{2}|[0-9]{3})$`)
+
+// hreflangLink is one <link rel="alternate" hreflang> entry.
+type hreflangLink struct {
+	Lang string // as written, e.g. "de-DE", "x-default"
+	Href string // resolved against the page URL
+}
+
+// hreflangLinks returns the hreflang alternates of the page.
+func hreflangLinks(html, base string) []hreflangLink {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var links []hreflangLink
+	doc.Find(`link[hreflang][href]`).Each(func(_ int, s *goquery.Selection) {
+		rel, _ := s.Attr("rel")
+		if !strings.Contains(" "+strings.ToLower(rel)+" ", " alternate ") {
+			return
+		}
+		lang, _ := s.Attr("hreflang")
+		href, _ := s.Attr("href")
+		links = append(links, hreflangLink{Lang: strings.TrimSpace(lang), Href: resolveURL(base, href)})
+	})
+	return links
+}
+
+// localeFromTag turns a BCP 47 tag into OG form: "de-de" becomes "de_DE",
+// "pt" stays "pt".
+func localeFromTag(tag string) string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return ""
+	}
+	lang := strings.ToLower(parts[0])
+	if len(parts) > 1 && (len(parts[len(parts)-1]) == 2 || len(parts[len(parts)-1]) == 3) {
+		return lang + "_" + strings.ToUpper(parts[len(parts)-1])
+	}
+	return lang
+}
+
+// localeMatches reports whether an og:locale agrees with a language tag:
+// the languages must match, and the territories too when the tag has one.
+func localeMatches(ogLocale, tag string) bool {
+	want := localeFromTag(tag)
+	got := localeFromTag(ogLocale)
+	if !strings.Contains(want, "_") {
+		return strings.SplitN(got, "_", 2)[0] == want
+	}
+	return got == want
+}
+
+// localeFindings validates og:locale and og:locale:alternate, compares them
+// with <html lang> and the hreflang alternates and, when fetch is set,
+// requests each alternate to check its og:locale and its link back to self.
+func localeFindings(ctx context.Context, html string, urls urlReport, fetch bool, workers int) []finding {
+	var out []finding
+	locale := strings.TrimSpace(parseOG(html)["locale"])
+	if locale != "" && !ogLocaleRe.MatchString(locale) {
+		example := localeFromTag(locale)
+		if !ogLocaleRe.MatchString(example) {
+			example = "en_US"
+		}
+		out = append(out, warnf("locale-format", "og:locale %q is not in ll_TT form (e.g. %s)", locale, example))
+	}
+
+	alternates := parseMetaAll(html, "og:locale:alternate")
+	seen := map[string]bool{}
+	for _, alt := range alternates {
+		alt = strings.TrimSpace(alt)
+		switch {
+		case !ogLocaleRe.MatchString(alt):
+			out = append(out, warnf("locale-alternate-format", "og:locale:alternate %q is not in ll_TT form", alt))
+		case alt == locale:
+			out = append(out, warnf("locale-alternate-self", "og:locale:alternate %s repeats og:locale", alt))
+		case seen[alt]:
+			out = append(out, warnf("locale-alternate-duplicate", "og:locale:alternate %s is declared twice", alt))
+		}
+		seen[alt] = true
+	}
+
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(html)); err == nil && locale != "" {
+		if lang, ok := doc.Find("html").Attr("lang"); ok && strings.TrimSpace(lang) != "" && !localeMatches(locale, lang) {
+			out = append(out, warnf("locale-html-lang-mismatch", "og:locale %s does not match <html lang=%q>; localized pages often ship the default locale", locale, lang))
+		}
+	}
+
+	links := hreflangLinks(html, urls.Final)
+	if len(links) == 0 {
+		return out
+	}
+	for _, alt := range alternates {
+		found := false
+		for _, l := range links {
+			if localeMatches(alt, l.Lang) {
+				found = true
+				break
+			}
+		}
+		if !found && ogLocaleRe.MatchString(alt) {
+			out = append(out, warnf("locale-alternate-no-hreflang", "og:locale:alternate %s has no matching <link rel=\"alternate\" hreflang>", alt))
+		}
+	}
+	var remote []hreflangLink
+	for _, l := range links {
+		if strings.EqualFold(l.Lang, "x-default") || isSelf(l.Href, urls) {
+			continue
+		}
+		if locale != "" && !localeMatches(locale, l.Lang) && !anyLocaleMatches(alternates, l.Lang) {
+			out = append(out, infof("hreflang-no-locale-alternate", "hreflang %s (%s) is not listed in og:locale:alternate", l.Lang, l.Href))
+		}
+		remote = append(remote, l)
+	}
+	if fetch {
+		out = append(out, checkAlternates(ctx, remote, urls, workers)...)
+	}
+	return out
+}
+
+// isSelf reports whether u names the page itself.
+func isSelf(u string, urls urlReport) bool {
+	return sameURL(u, urls.Final) || sameURL(u, urls.Requested) || (urls.Canonical != "" && sameURL(u, urls.Canonical))
+}
+
+// anyLocaleMatches reports whether any of locales agrees with tag.
+func anyLocaleMatches(locales []string, tag string) bool {
+	for _, l := range locales {
+		if localeMatches(l, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAlternates fetches every alternate with at most workers requests in
+// flight and checks its og:locale against the hreflang value and that it
+// lists the page among its own alternates.
+func checkAlternates(ctx context.Context, links []hreflangLink, urls urlReport, workers int) []finding {
+	results := make([][]finding, len(links))
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, l := range links {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			p, err := fetchPage(ctx, l.Href)
+			if err != nil {
+				results[i] = []finding{warnf("hreflang-unreachable", "hreflang %s alternate %s cannot be fetched: %v", l.Lang, l.Href, err)}
+				return
+			}
+			var fs []finding
+			switch loc := strings.TrimSpace(parseOG(p.HTML)["locale"]); {
+			case loc == "":
+				fs = append(fs, warnf("hreflang-locale-mismatch", "hreflang %s alternate %s has no og:locale", l.Lang, l.Href))
+			case !localeMatches(loc, l.Lang):
+				fs = append(fs, warnf("hreflang-locale-mismatch", "hreflang %s alternate %s declares og:locale %s", l.Lang, l.Href, loc))
+			}
+			back := false
+			for _, bl := range hreflangLinks(p.HTML, pick(p.FinalURL, l.Href)) {
+				if isSelf(bl.Href, urls) {
+					back = true
+					break
+				}
+			}
+			if !back {
+				fs = append(fs, warnf("hreflang-no-backlink", "hreflang %s alternate %s does not link back to %s", l.Lang, l.Href, urls.Final))
+			}
+			results[i] = fs
+		}()
+	}
+	wg.Wait()
+
+	var out []finding
+	for _, fs := range results {
+		out = append(out, fs...)
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLocaleMatches(t *testing.T) {
+	for _, c := range []struct {
+		locale, tag string
+		want        bool
+	}{
+		{"de_DE", "de-DE", true},
+		{"de_DE", "de", true},
+		{"de_AT", "de-de", false},
+		{"en_US", "de", false},
+		{"es_419", "es-419", true},
+		{"zh_TW", "zh-Hant-TW", true},
+	} {
+		if got := localeMatches(c.locale, c.tag); got != c.want {
+			t.Errorf("localeMatches(%q, %q) = %v, want %v", c.locale, c.tag, got, c.want)
+		}
+	}
+}
+
+func TestLocaleFindings(t *testing.T) {
+	var srv *httptest.Server
+	page := func(lang, locale string, alternates ...string) string {
+		var b strings.Builder
+		fmt.Fprintf(&b, `<html lang="%s"><head><meta property="og:locale" content="%s">`, lang, locale)
+		for _, a := range alternates {
+			parts := strings.SplitN(a, "=", 2)
+			fmt.Fprintf(&b, `<link rel="alternate" hreflang="%s" href="%s%s">`, parts[0], srv.URL, parts[1])
+		}
+		return b.String() + "</head></html>"
+	}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/de": // ships the English locale and does not link back
+			fmt.Fprint(w, page("de", "en_US", "de=/de"))
+		case "/fr":
+			fmt.Fprint(w, page("fr", "fr_FR", "en=/en", "fr=/fr"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	html := page("en", "en-US", "en=/en", "de=/de", "fr=/fr", "it=/it", "x-default=/en")
+	html = strings.Replace(html, "</head>", `<meta property="og:locale:alternate" content="de_DE"><meta property="og:locale:alternate" content="es_ES"><meta property="og:locale:alternate" content="de_DE"></head>`, 1)
+	urls := urlReport{Requested: srv.URL + "/en", Final: srv.URL + "/en"}
+
+	got := ruleIDs(localeFindings(context.Background(), html, urls, true, 2))
+	for _, want := range []string{
+		"locale-format",
+		"locale-alternate-duplicate",
+		"locale-alternate-no-hreflang",
+		"hreflang-no-locale-alternate",
+		"hreflang-locale-mismatch",
+		"hreflang-no-backlink",
+		"hreflang-unreachable",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rules %q lack %s", got, want)
+		}
+	}
+	if strings.Count(got, "hreflang-no-backlink") != 1 {
+		t.Errorf("only /de lacks a backlink: %q", got)
+	}
+
+	if got := ruleIDs(localeFindings(context.Background(), page("de", "en_US"), urls, false, 1)); got != "locale-html-lang-mismatch" {
+		t.Errorf("html lang mismatch: rules = %q", got)
+	}
+}
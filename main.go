@@ -26,19 +26,16 @@ package main
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"image"
-	_ "image/gif"
-	"io"
 	"log/slog"
-	"math"
 	"net/http"
+	"net/url"
 	"os"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -60,15 +57,7 @@ const (
 	userAgent      = "OGSPY/" + version + " (https://github.com/vincenzomaritato/ogspy)"
 )
 
-var (
-	// essentialTags must be present for a shareable preview to work correctly.
-	essentialTags = []string{"title", "type", "image", "url", "description"}
-
-	// recommendedTags is the superset checked by the default validation command.
-	recommendedTags = append(append([]string{}, essentialTags...), "site_name", "locale", "video", "audio", "article:author", "article:publisher", "article:section", "article:tag")
-)
-
-// logger is populated in newRootCmd().PersistentPreRun.
+// logger is populated in newRootCmd().PersistentPreRunE.
 var logger *slog.Logger
 
 // isTerminal reports whether stdout is a terminal; colour output should be disabled otherwise.
@@ -80,68 +69,27 @@ func isTerminal() bool {
 	return (fi.Mode() & os.ModeCharDevice) != 0
 }
 
-// checkImage downloads the image and validates size, dimensions and aspect‑ratio.
-func checkImage(imgURL string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
-
-	// HEAD first to check size
-	req, _ := http.NewRequestWithContext(ctx, http.MethodHead, imgURL, nil)
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("cannot HEAD og:image: %w", err)
-	}
-	resp.Body.Close()
-	if cl := resp.Header.Get("Content-Length"); cl != "" {
-		if size, _ := strconv.ParseInt(cl, 10, 64); size > 5*1024*1024 {
-			return fmt.Errorf("og:image is larger than 5 MB")
-		}
-	}
-
-	// Download full image (limit 5 MB)
-	resp, err = http.Get(imgURL)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	data, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
-	if err != nil {
-		return err
-	}
-	img, _, err := image.DecodeConfig(bytes.NewReader(data))
-	if err != nil {
-		return fmt.Errorf("cannot decode og:image: %w", err)
-	}
-	if img.Width < 1200 || img.Height < 630 {
-		return fmt.Errorf("og:image resolution too small (%dx%d)", img.Width, img.Height)
-	}
-	ratio := float64(img.Width) / float64(img.Height)
-	if math.Abs(ratio-1.91) > 0.1 {
-		return fmt.Errorf("og:image aspect ratio %.2f deviates from 1.91:1", ratio)
-	}
-	return nil
-}
-
-// semanticValidate returns warnings about advanced semantic rules.
-func semanticValidate(og map[string]string) []string {
-	var warns []string
+// semanticValidate returns warnings about the image behind og:image, resolved
+// against the page URL base and probed within ctx. Property presence and value
+// checks live in the rule set (see rules.go).
+func semanticValidate(ctx context.Context, og map[string]string, base string) []finding {
+	var warns []finding
 
-	if imgURL, ok := og["image"]; ok && imgURL != "" {
+	if img := strings.TrimSpace(og["image"]); img != "" {
+		imgURL := resolveURL(base, img)
 		if !strings.HasPrefix(imgURL, "https://") {
-			warns = append(warns, "og:image should use HTTPS")
+			warns = append(warns, warnf("image-https", "og:image should use HTTPS"))
 		}
-		if err := checkImage(imgURL); err != nil {
-			warns = append(warns, err.Error())
-		}
-	}
-
-	switch og["type"] {
-	case "article":
-		if og["article:author"] == "" {
-			warns = append(warns, "article:author is missing")
-		}
-		if og["article:section"] == "" {
-			warns = append(warns, "article:section is missing")
+		if info, err := probeImage(ctx, imgURL); err != nil {
+			rule := "image-unreachable"
+			if errors.Is(err, errSVG) {
+				rule = "image-svg"
+			}
+			warns = append(warns, warnf(rule, "%s", err))
+		} else {
+			warns = append(warns, imageWarnings(info)...)
+			warns = append(warns, declaredImageWarnings(info, og)...)
+			warns = append(warns, imageAdvice(info)...)
 		}
 	}
 
@@ -152,43 +100,66 @@ func semanticValidate(og map[string]string) []string {
 // HTTP Layer
 // ------------------------------------------------------------------------------------------------
 
+// page is a fetched HTML document together with its network timing.
+type page struct {
+	HTML     string
+	Status   int
+	FinalURL string // after redirects
+	Timing   timings
+}
+
 // fetchHTML performs a GET request with context/timeout management and returns
 // the retrieved HTML document as a string.
 func fetchHTML(ctx context.Context, url string) (string, error) {
-	start := time.Now()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	p, err := fetchPage(ctx, url)
 	if err != nil {
 		return "", err
 	}
+	return p.HTML, nil
+}
+
+// fetchPage is fetchHTML plus the DNS/connect/TLS/TTFB/download breakdown
+// captured with httptrace.
+func fetchPage(ctx context.Context, url string) (*page, error) {
+	ctx, tt := traceContext(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("User-Agent", userAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 
-	client := &http.Client{
-		Timeout: defaultTimeout,
-	}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	t := tt.done()
 	html, err := doc.Html()
 	if logger != nil {
 		logger.Debug("http.fetch",
 			slog.String("url", url),
 			slog.Int("status", resp.StatusCode),
-			slog.Duration("elapsed", time.Since(start)),
+			slog.Duration("dns", t.DNS),
+			slog.Duration("connect", t.Connect),
+			slog.Duration("tls", t.TLS),
+			slog.Duration("ttfb", t.TTFB),
+			slog.Duration("elapsed", t.Total),
 		)
 	}
-	return html, err
+	if err != nil {
+		return nil, err
+	}
+	return &page{HTML: html, Status: resp.StatusCode, FinalURL: resp.Request.URL.String(), Timing: t}, nil
 }
 
 // ------------------------------------------------------------------------------------------------
@@ -199,25 +170,105 @@ func fetchHTML(ctx context.Context, url string) (string, error) {
 // property attribute starts with "og:"; the returned map is keyed without the
 // "og:" prefix (e.g. "og:title" becomes "title").
 func parseOG(html string) map[string]string {
+	return parseMeta(html, "og:")
+}
+
+// parseTwitter is parseOG for Twitter Card tags ("twitter:player" becomes
+// "player").
+func parseTwitter(html string) map[string]string {
+	return parseMeta(html, "twitter:")
+}
+
+// parseMeta extracts every meta tag whose name or property attribute starts
+// with prefix, keyed without the prefix.
+func parseMeta(html, prefix string) map[string]string {
 	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(html))
-	og := make(map[string]string)
+	tags := make(map[string]string)
 
 	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
 		// Handle <meta property="og:..." content="...">
-		if prop, ok := s.Attr("property"); ok && strings.HasPrefix(prop, "og:") {
+		if prop, ok := s.Attr("property"); ok && strings.HasPrefix(prop, prefix) {
 			if content, ok := s.Attr("content"); ok {
-				og[strings.TrimPrefix(prop, "og:")] = content
+				tags[strings.TrimPrefix(prop, prefix)] = content
 			}
 		}
 
 		// Handle <meta name="og:..." content="...">
-		if name, ok := s.Attr("name"); ok && strings.HasPrefix(name, "og:") {
+		if name, ok := s.Attr("name"); ok && strings.HasPrefix(name, prefix) {
 			if content, ok := s.Attr("content"); ok {
-				og[strings.TrimPrefix(name, "og:")] = content
+				tags[strings.TrimPrefix(name, prefix)] = content
 			}
 		}
 	})
-	return og
+	return tags
+}
+
+// parseMetaAll returns every content value of the meta tags whose property
+// or name equals key, in document order; structured properties such as
+// og:locale:alternate and article:tag may repeat.
+func parseMetaAll(html, key string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var values []string
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		prop, ok := s.Attr("property")
+		if !ok {
+			prop, _ = s.Attr("name")
+		}
+		if content, ok := s.Attr("content"); ok && prop == key {
+			values = append(values, content)
+		}
+	})
+	return values
+}
+
+// fallbackData holds the non-OG metadata platforms fall back on when OG tags
+// are missing.
+type fallbackData struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Canonical   string `json:"canonical,omitempty"`
+	Image       string `json:"image,omitempty"`
+	ThemeColor  string `json:"theme_color,omitempty"`
+}
+
+// parseFallbacks extracts <title>, the meta description, rel=canonical, the
+// first page image and theme-color. Relative URLs are resolved against base.
+func parseFallbacks(html, base string) fallbackData {
+	var fb fallbackData
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return fb
+	}
+	fb.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	fb.Description, _ = doc.Find(`meta[name="description"]`).First().Attr("content")
+	fb.ThemeColor, _ = doc.Find(`meta[name="theme-color"]`).First().Attr("content")
+	if href, ok := doc.Find(`link[rel="canonical"]`).First().Attr("href"); ok {
+		fb.Canonical = resolveURL(base, href)
+	}
+	if href, ok := doc.Find(`link[rel="image_src"]`).First().Attr("href"); ok {
+		fb.Image = resolveURL(base, href)
+	} else if src, ok := doc.Find("body img[src]").First().Attr("src"); ok {
+		fb.Image = resolveURL(base, src)
+	}
+	return fb
+}
+
+// resolveURL resolves ref against base, returning ref unchanged when either
+// cannot be parsed.
+func resolveURL(base, ref string) string {
+	ref = strings.TrimSpace(ref)
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := b.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return r.String()
 }
 
 // diffMaps returns the set of keys that differ between two OG maps; for each
@@ -247,6 +298,31 @@ func diffMaps(old, new map[string]string) map[string][2]string {
 // Presentation Helpers
 // ------------------------------------------------------------------------------------------------
 
+// inspectReport is the per-URL payload of `inspect --json --json-version 2`;
+// version 1 keeps only OG.
+type inspectReport struct {
+	OG       map[string]string `json:"og"`
+	Custom   map[string]string `json:"custom,omitempty"` // properties in namespaces declared via the prefix attribute
+	Timing   *timingReport     `json:"timing,omitempty"`
+	SPA      *spaReport        `json:"client_side,omitempty"`
+	Links    []linkResult      `json:"links,omitempty"`
+	Crops    []cropResult      `json:"crops,omitempty"`
+	URLs     *urlReport        `json:"urls,omitempty"`
+	Previews []textFit         `json:"previews,omitempty"`
+	Score    *score            `json:"score,omitempty"`
+	Suggest  string            `json:"suggest,omitempty"` // --suggest snippet
+	Findings []finding         `json:"findings,omitempty"`
+
+	cards     cardSources // OG, Twitter Card and fallback data for the HTML report
+	htmlCards []card      // with --html, the simulated cards with embedded images
+}
+
+// timingReport groups the page timing with the timing of each og:image.
+type timingReport struct {
+	Page   timings            `json:"page"`
+	Images map[string]timings `json:"images,omitempty"`
+}
+
 // printTable renders the OG map as a compact, colourised table with a header.
 func printTable(og map[string]string) {
 	keys := make([]string, 0, len(og))
@@ -266,36 +342,174 @@ func printTable(og map[string]string) {
 	}
 }
 
-// printMissing highlights absent tags and returns an exit‑code‑style integer
-// (0 when all tags are present, 1 otherwise).
-func printMissing(og map[string]string, essentialsOnly bool) int {
-	required := recommendedTags
-	if essentialsOnly {
-		required = essentialTags
+// printCustom lists the properties of custom namespaces below the OG table.
+func printCustom(props map[string]string) {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		color.New(color.FgCyan, color.Bold).Printf("%-18s", k)
+		fmt.Printf(" %s\n", props[k])
+	}
+}
+
+// printTiming renders the network timing breakdown below the OG table.
+func printTiming(tr *timingReport) {
+	dim := color.New(color.FgHiBlack)
+	fmt.Println()
+	color.New(color.FgCyan, color.Bold).Printf("%-18s", "timing:page")
+	dim.Printf(" %s\n", tr.Page)
+	imgs := make([]string, 0, len(tr.Images))
+	for u := range tr.Images {
+		imgs = append(imgs, u)
+	}
+	sort.Strings(imgs)
+	for _, u := range imgs {
+		color.New(color.FgCyan, color.Bold).Printf("%-18s", "timing:og:image")
+		dim.Printf(" %s  %s\n", tr.Images[u], u)
+	}
+}
+
+// printLinks lists the link-check outcome of every URL-valued property.
+func printLinks(links []linkResult) {
+	fmt.Println()
+	for _, l := range links {
+		color.New(color.FgCyan, color.Bold).Printf("%-18s", "link:"+l.Property)
+		switch {
+		case l.Dead():
+			status := l.Error
+			if status == "" {
+				status = strconv.Itoa(l.Status)
+			}
+			color.New(color.FgRed).Printf(" ✘ %s  %s\n", l.URL, status)
+		case l.FinalURL != "":
+			color.New(color.FgYellow).Printf(" ↪ %s → %s (%d)\n", l.URL, l.FinalURL, l.Status)
+		default:
+			color.New(color.FgGreen).Printf(" ✔ %s (%d)\n", l.URL, l.Status)
+		}
+	}
+}
+
+// printCrops reports how much of og:image each platform crop cuts away.
+func printCrops(crops []cropResult) {
+	fmt.Println()
+	for _, cr := range crops {
+		color.New(color.FgCyan, color.Bold).Printf("%-18s", "crop:"+cr.Platform)
+		lossColor := color.New(color.FgGreen)
+		switch {
+		case cr.LossPercent >= 30:
+			lossColor = color.New(color.FgRed)
+		case cr.LossPercent >= 10:
+			lossColor = color.New(color.FgYellow)
+		}
+		lossColor.Printf(" %4.1f%% lost", cr.LossPercent)
+		fmt.Printf("  %dx%d", cr.Width, cr.Height)
+		if cr.Trimmed != "" {
+			fmt.Printf(" (%s)", cr.Trimmed)
+		}
+		color.New(color.FgHiBlack).Printf("  %s\n", cr.File)
 	}
+}
 
-	missing := make([]string, 0)
-	for _, k := range required {
-		if og[k] == "" {
-			missing = append(missing, "og:"+k)
+// printSPA explains why missing tags are probably injected by JavaScript.
+func printSPA(spa *spaReport) {
+	if spa == nil || !spa.Likely {
+		return
+	}
+	color.New(color.FgYellow, color.Bold).Println("⚠ Tags likely injected client-side – crawlers will not see them; render them server-side (SSR/prerender):")
+	for _, e := range spa.Evidence {
+		fmt.Printf("  • %s\n", e)
+	}
+}
+
+// printCategory prints a titled group of findings, or nothing when empty.
+func printCategory(title string, findings []finding) {
+	if len(findings) == 0 {
+		return
+	}
+	color.New(color.FgYellow, color.Bold).Printf("\n%s (%d):\n", title, len(findings))
+	for _, f := range findings {
+		printFinding("  ", f)
+	}
+}
+
+// printFinding prints one finding with a severity icon and its rule ID.
+func printFinding(indent string, f finding) {
+	c, icon := color.New(color.FgYellow), "⚠"
+	switch f.Severity {
+	case sevError:
+		c, icon = color.New(color.FgRed), "✘"
+	case sevInfo:
+		c, icon = color.New(color.FgCyan), "ℹ"
+	}
+	c.Printf("%s%s %s", indent, icon, f.Message)
+	color.New(color.FgHiBlack).Printf(" [%s]\n", f.Rule)
+}
+
+// printWarnings prints the findings below error severity, one per line.
+// Client-side injection is left to printSPA.
+func printWarnings(fs []finding) {
+	for _, f := range fs {
+		if f.Severity != sevError && f.Rule != "client-side-tags" {
+			printFinding("", f)
 		}
 	}
+}
 
+// printMissing highlights absent tags and other error-severity findings.
+func printMissing(fs []finding) {
+	var missing, invalid []finding
+	for _, f := range fs {
+		switch {
+		case f.Severity != sevError, f.Rule == "snapshot-changed": // shown as a diff
+		case f.Missing:
+			missing = append(missing, f)
+		default:
+			invalid = append(invalid, f)
+		}
+	}
+	dim := color.New(color.FgHiBlack)
 	if len(missing) > 0 {
 		color.New(color.FgRed, color.Bold).Printf("\n✘ Missing Open Graph tags (%d):\n", len(missing))
-		for _, tag := range missing {
-			fmt.Printf("  • %s\n", tag)
+		for _, f := range missing {
+			fmt.Printf("  • %s", f.Property)
+			dim.Printf(" [%s]\n", f.Rule)
 		}
-		return 1
 	}
+	if len(invalid) > 0 {
+		color.New(color.FgRed, color.Bold).Printf("\n✘ Invalid Open Graph tags (%d):\n", len(invalid))
+		for _, f := range invalid {
+			fmt.Printf("  • %s", f.Message)
+			dim.Printf(" [%s]\n", f.Rule)
+		}
+	}
+	if len(missing)+len(invalid) == 0 {
+		color.New(color.FgGreen, color.Bold).Println("✔ All required tags are present.")
+	}
+}
 
-	color.New(color.FgGreen, color.Bold).Println("✔ All required tags are present.")
-	return 0
+// printImageChange reports a picture swap behind an unchanged og:image URL.
+func printImageChange(ch *imageChange) {
+	color.New(color.FgCyan, color.Bold).Print("og:image")
+	if ch.Distance >= 0 {
+		fmt.Printf(" content changed (perceptual distance %d/64) ", ch.Distance)
+	} else {
+		fmt.Print(" content changed (bytes differ) ")
+	}
+	color.New(color.FgHiBlack).Println(ch.URL)
 }
 
 // printUnified renders a unified diff (à la git) for a given OG diff map.
 func printUnified(diff map[string][2]string) {
-	for k, v := range diff {
+	keys := make([]string, 0, len(diff))
+	for k := range diff {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := diff[k]
 		fmt.Printf("@@ og:%s @@\n", k)
 		if v[0] != "" {
 			fmt.Printf("- %s\n", v[0])
@@ -314,12 +528,13 @@ func newRootCmd() *cobra.Command {
 	var noColor bool
 	var logJSON bool
 	var logLevel string
+	var netOpts transportOptions
 
 	cmd := &cobra.Command{
 		Use:     "ogspy",
 		Short:   "Lightweight CLI tool to inspect, validate and monitor Open Graph metadata.",
 		Version: version,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Auto-disable colour when requested or when stdout is not a TTY.
 			if noColor || !isTerminal() || os.Getenv("NO_COLOR") != "" {
 				color.NoColor = true
@@ -343,126 +558,325 @@ func newRootCmd() *cobra.Command {
 			}
 			logger = slog.New(handler)
 			slog.SetDefault(logger)
+
+			// Shared HTTP transport -----------------------------------------
+			client, err := newHTTPClient(netOpts)
+			if err != nil {
+				return err
+			}
+			httpClient = client
+			return nil
 		},
 	}
 
 	cmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable coloured output")
 	cmd.PersistentFlags().BoolVar(&logJSON, "log-json", false, "Emit logs as newline-delimited JSON")
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level: debug, info, warn, error")
-	cmd.AddCommand(newInspectCmd(), newValidateCmd(), newMonitorCmd())
+	cmd.PersistentFlags().StringArrayVar(&netOpts.resolve, "resolve", nil, "Force host:port to connect to addr, curl-style (host:port:addr, repeatable)")
+	cmd.PersistentFlags().StringVar(&netOpts.dnsServer, "dns-server", "", "Resolve hostnames through this DNS server (addr[:port])")
+	cmd.PersistentFlags().BoolVar(&netOpts.policy.safe, "safe-fetch", false, "Refuse to fetch loopback, private, link-local and cloud metadata addresses (checked on every redirect and image fetch)")
+	cmd.PersistentFlags().StringSliceVar(&netOpts.policy.allowHosts, "allow-host", nil, "Only fetch these hosts (names, *.suffix wildcards, IPs or CIDRs)")
+	cmd.PersistentFlags().StringSliceVar(&netOpts.policy.denyHosts, "deny-host", nil, "Never fetch these hosts (names, *.suffix wildcards, IPs or CIDRs)")
+	cmd.PersistentFlags().IntSliceVar(&netOpts.policy.allowPorts, "allow-port", nil, "Only connect to these ports")
+	cmd.PersistentFlags().IntSliceVar(&netOpts.policy.denyPorts, "deny-port", nil, "Never connect to these ports")
+	cmd.AddCommand(newInspectCmd(), newValidateCmd(), newMonitorCmd(), newSnapshotCmd())
 	return cmd
 }
 
+// ------------------------------------------------------------------------------------------------
+// URL Input & Worker Pool
+// ------------------------------------------------------------------------------------------------
+
+// readURLs collects the URLs named on the command line; "-" reads one URL per
+// line from STDIN.
+func readURLs(args []string) ([]string, error) {
+	var urls []string
+	for _, a := range args {
+		if a != "-" {
+			urls = append(urls, a)
+			continue
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				urls = append(urls, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if len(urls) == 0 {
+		return nil, errors.New("no URLs provided")
+	}
+	return urls, nil
+}
+
+// runPool calls fn for every URL on at most workers goroutines (one per CPU
+// when workers <= 0) and delivers the results in completion order. The
+// channel is closed once every URL is done.
+func runPool[T any](urls []string, workers int, fn func(u string) T) <-chan T {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(urls))
+
+	tasks := make(chan string)
+	results := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range tasks {
+				results <- fn(u)
+			}
+		}()
+	}
+	go func() {
+		for _, u := range urls {
+			tasks <- u
+		}
+		close(tasks)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
 // ------------------------------------------------------------------------------------------------
 // Inspect Command (concurrent worker‑pool)
 // ------------------------------------------------------------------------------------------------
 func newInspectCmd() *cobra.Command {
 	var jsonOut bool
+	var jsonVersion int
 	var timeout int
 	var workers int
+	var showTiming bool
+	var budget crawlerBudget
+	var checkURLs bool
+	var linkWorkers int
+	var htmlOut string
+	var cropDir string
+	var rulesFile string
+	var format string
+	var output string
+	var suggest string
 
 	c := &cobra.Command{
 		Use:   "inspect URL [URL...]",
 		Short: "Inspect Open Graph metadata for one or many URLs (use “-” to read from STDIN)",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Collect URLs from args / STDIN
-			var urls []string
-			for _, a := range args {
-				if a == "-" {
-					scanner := bufio.NewScanner(os.Stdin)
-					for scanner.Scan() {
-						line := strings.TrimSpace(scanner.Text())
-						if line != "" {
-							urls = append(urls, line)
-						}
-					}
-					if err := scanner.Err(); err != nil {
-						return err
-					}
-				} else {
-					urls = append(urls, a)
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+			if suggest != "" {
+				if err := checkSuggestFlavor(suggest); err != nil {
+					return err
 				}
 			}
-			if len(urls) == 0 {
-				return errors.New("no URLs provided")
+			if jsonVersion != 1 && jsonVersion != 2 {
+				return fmt.Errorf("unknown --json-version %d (want 1 or 2)", jsonVersion)
 			}
-
-			// Worker‑pool setup
-			type result struct {
-				url string
-				og  map[string]string
-				err error
+			if jsonOut && format != "text" && output == "" {
+				return errors.New("--json and --format both write to STDOUT; send the report to a file with --output")
 			}
-			tasks := make(chan string)
-			results := make(chan result)
-			var wg sync.WaitGroup
-
-			if workers <= 0 {
-				workers = runtime.NumCPU()
+			// A report on STDOUT replaces the table output.
+			quiet := format != "text" && output == ""
+			urls, err := readURLs(args)
+			if err != nil {
+				return err
 			}
-			if workers > len(urls) {
-				workers = len(urls)
+			rules, err := loadRules(rulesFile)
+			if err != nil {
+				return err
 			}
 
-			// Spawn workers
-			for i := 0; i < workers; i++ {
-				wg.Add(1)
-				go func() {
-					defer wg.Done()
-					for u := range tasks {
+			type result struct {
+				url    string
+				report inspectReport
+				err    error
+			}
+			results := runPool(urls, workers, func(u string) result {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				p, err := fetchPage(ctx, u)
+				cancel()
+				if err != nil {
+					return result{url: u, err: err}
+				}
+				rep := inspectReport{OG: parseOG(p.HTML)}
+				rep.cards = cardSources{pageURL: u, og: rep.OG, twitter: parseTwitter(p.HTML), fallback: parseFallbacks(p.HTML, p.FinalURL)}
+				meta := parseMeta(p.HTML, "")
+				ns := parseNamespaces(p.HTML)
+				rep.Custom = customProperties(meta, ns)
+				rep.Findings = rules.check(meta, false, false)
+				rep.Findings = append(rep.Findings, ogTypeFindings(meta, ns)...)
+				if spa := detectSPA(p.HTML, rep.OG); spa != nil && spa.Likely {
+					rep.SPA = spa
+					rep.Findings = append(rep.Findings, spa.finding())
+				}
+				rep.Previews = textFits(rep.cards)
+				rep.Findings = append(rep.Findings, fitFindings(rep.Previews)...)
+				rep.Findings = append(rep.Findings, articleFindings(p.HTML, rep.OG, time.Now())...)
+				rep.Findings = append(rep.Findings, budgetWarnings("page", p.Timing, budget)...)
+				if showTiming {
+					rep.Timing = &timingReport{Page: p.Timing}
+					for _, img := range imageURLs(rep.OG, p.FinalURL) {
 						ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-						html, err := fetchHTML(ctx, u)
+						t, err := timeFetch(ctx, img)
 						cancel()
 						if err != nil {
-							results <- result{url: u, err: err}
+							rep.Findings = append(rep.Findings, warnf("image-unreachable", "cannot fetch og:image %s: %v", img, err))
 							continue
 						}
-						results <- result{url: u, og: parseOG(html)}
+						if rep.Timing.Images == nil {
+							rep.Timing.Images = make(map[string]timings)
+						}
+						rep.Timing.Images[img] = t
+						rep.Findings = append(rep.Findings, budgetWarnings("og:image "+img, t, budget)...)
 					}
-				}()
-			}
-
-			// Feed tasks
-			go func() {
-				for _, u := range urls {
-					tasks <- u
 				}
-				close(tasks)
-			}()
-
-			// Close results when all workers return
-			go func() {
-				wg.Wait()
-				close(results)
-			}()
+				pageURLs := newURLReport(u, p, rep.OG)
+				rep.URLs = &pageURLs
+				ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				rep.Findings = append(rep.Findings, canonicalFindings(ctx, pageURLs, checkURLs)...)
+				cancel()
+				ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				rep.Findings = append(rep.Findings, localeFindings(ctx, p.HTML, pageURLs, checkURLs, linkWorkers)...)
+				cancel()
+				if checkURLs {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					rep.Links = checkLinks(ctx, linkTargets(p.HTML), linkWorkers)
+					cancel()
+					rep.Findings = append(rep.Findings, linkWarnings(rep.Links)...)
+				}
+				if img := rep.OG["image"]; cropDir != "" && img != "" {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					crops, err := writeCrops(ctx, resolveURL(u, img), u, cropDir)
+					cancel()
+					if err != nil {
+						rep.Findings = append(rep.Findings, warnf("image-crop", "%s", err))
+					}
+					rep.Crops = crops
+				}
+				rep.Findings, _ = rules.suppress(u, p.HTML, rep.Findings)
+				if suggest != "" {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					rep.Suggest = suggestSnippet(ctx, rep.Findings, p.HTML, pageURLs.Final, rep.cards.fallback, suggest)
+					cancel()
+				}
+				sc := computeScore(rep.Findings)
+				sc.Partial = true
+				rep.Score = &sc
+				if htmlOut != "" {
+					rep.htmlCards = buildCards(rep.cards)
+					embedImages(rep.htmlCards, time.Duration(timeout)*time.Second)
+				}
+				return result{url: u, report: rep}
+			})
 
 			exitCode := 0
-			aggregated := make(map[string]map[string]string)
+			aggregated := make(map[string]inspectReport)
+			var entries []reportEntry
+			var ci []ciResult
+			scores := make(map[string]int)
 
 			for r := range results {
+				if htmlOut != "" {
+					e := reportEntry{URL: r.url}
+					if r.err != nil {
+						e.Error = r.err.Error()
+					} else {
+						e.Cards = r.report.htmlCards
+						e.Findings = r.report.Findings
+					}
+					entries = append(entries, e)
+				}
+				if format != "text" {
+					// inspect only fails on fetch errors, so no finding fails a URL.
+					ci = append(ci, ciResult{URL: r.url, Err: r.err, Findings: r.report.Findings})
+				}
 				if r.err != nil {
-					color.Red("Error fetching %s: %v", r.url, r.err)
+					if !quiet {
+						color.Red("Error fetching %s: %v", r.url, r.err)
+					}
 					exitCode = 1
 					continue
 				}
-				if jsonOut {
-					aggregated[r.url] = r.og
-				} else {
+				switch {
+				case jsonOut:
+					aggregated[r.url] = r.report
+				case !quiet:
 					color.New(color.FgMagenta, color.Bold).Printf("\n[%s]\n", r.url)
-					printTable(r.og)
+					printTable(r.report.OG)
+					if len(r.report.Custom) > 0 {
+						printCustom(r.report.Custom)
+					}
+					if r.report.Timing != nil {
+						printTiming(r.report.Timing)
+					}
+					if len(r.report.Links) > 0 {
+						printLinks(r.report.Links)
+					}
+					if len(r.report.Crops) > 0 {
+						printCrops(r.report.Crops)
+					}
 					fmt.Println()
-					printMissing(r.og, false)
+					if hasRule(r.report.Findings, "client-side-tags") {
+						printSPA(r.report.SPA)
+					}
+					printWarnings(r.report.Findings)
+					printMissing(r.report.Findings)
+					printSuggestion(r.report.Suggest)
+					printScore(*r.report.Score)
 				}
+				scores[r.url] = r.report.Score.Total
 			}
 
+			if !jsonOut && !quiet && len(scores) > 1 {
+				printSiteAverages(scores)
+			}
+			if format != "text" {
+				order := make(map[string]int, len(urls))
+				for i := len(urls) - 1; i >= 0; i-- {
+					order[urls[i]] = i
+				}
+				sort.SliceStable(ci, func(i, j int) bool { return order[ci[i].URL] < order[ci[j].URL] })
+				if err := writeCIReportFile(output, format, "inspect", ci); err != nil {
+					return err
+				}
+			}
 			if jsonOut {
 				enc := json.NewEncoder(os.Stdout)
 				enc.SetIndent("", "  ")
-				if err := enc.Encode(aggregated); err != nil {
+				var v any = aggregated
+				if jsonVersion == 1 {
+					tags := make(map[string]map[string]string, len(aggregated))
+					for u, rep := range aggregated {
+						tags[u] = rep.OG
+					}
+					v = tags
+				}
+				if err := enc.Encode(v); err != nil {
 					return err
 				}
 			}
+			if htmlOut != "" {
+				order := make(map[string]int, len(urls))
+				for i, u := range urls {
+					if _, ok := order[u]; !ok {
+						order[u] = i
+					}
+				}
+				sort.SliceStable(entries, func(i, j int) bool { return order[entries[i].URL] < order[entries[j].URL] })
+				if err := writeHTMLReport(htmlOut, entries); err != nil {
+					return err
+				}
+				color.New(color.FgGreen).Fprintf(os.Stderr, "✔ HTML report written to %s\n", htmlOut)
+			}
 			if exitCode != 0 {
 				return errors.New("one or more URLs failed inspection")
 			}
@@ -471,8 +885,20 @@ func newInspectCmd() *cobra.Command {
 	}
 
 	c.Flags().BoolVarP(&jsonOut, "json", "j", false, "Output raw JSON instead of a table")
+	c.Flags().IntVar(&jsonVersion, "json-version", 1, "--json shape: 1 maps each URL to its tags, 2 to an object with tags, findings, score, timing, links, …")
 	c.Flags().IntVarP(&timeout, "timeout", "t", int(defaultTimeout.Seconds()), "HTTP timeout in seconds")
 	c.Flags().IntVarP(&workers, "workers", "w", runtime.NumCPU(), "Number of concurrent workers")
+	c.Flags().BoolVar(&showTiming, "timing", false, "Show DNS/connect/TLS/TTFB/download timings for the page and each og:image")
+	c.Flags().BoolVar(&checkURLs, "links", false, "Link-check every URL-valued property (og:url, og:see_also, article:author, al:*, …)")
+	c.Flags().IntVar(&linkWorkers, "link-workers", 4, "Maximum concurrent link-check requests per URL")
+	c.Flags().StringVar(&cropDir, "crops", "", "Write og:image as cropped by each platform (one PNG per platform) into this directory and report what each crop loses")
+	c.Flags().StringVar(&rulesFile, "rules", "", "Check properties against this JSON rules file instead of the built-in rules")
+	c.Flags().StringVar(&format, "format", "text", "Report format: text, junit, sarif, tap or github (only fetch errors count as failures)")
+	c.Flags().StringVarP(&output, "output", "o", "", "Write the --format report to this file instead of STDOUT")
+	c.Flags().StringVar(&suggest, "suggest", "", "Print ready-to-paste meta tags for missing or invalid properties: html (default), jsx or gotmpl")
+	c.Flags().Lookup("suggest").NoOptDefVal = "html"
+	c.Flags().StringVar(&htmlOut, "html", "", "Also write a self-contained HTML report with simulated Facebook, X, LinkedIn, Slack and Discord cards to this file")
+	budget.addFlags(c)
 	return c
 }
 
@@ -480,41 +906,356 @@ func newInspectCmd() *cobra.Command {
 // Validate Command
 // ------------------------------------------------------------------------------------------------
 
+// validateResult is the outcome of validating one URL.
+type validateResult struct {
+	URL        string
+	Err        error     // the page could not be fetched
+	Findings   []finding // after suppression
+	A11y       []finding // accessibility findings, after suppression
+	Suppressed int
+	Failed     []finding // findings at or above --fail-on
+	Score      score
+	LowScore   bool // Score.Total is below --min-score
+
+	timing   *timingReport
+	spa      *spaReport
+	snapshot *snapshotCheck // with --against
+	suggest  string         // --suggest snippet
+}
+
+// Passed reports whether the URL was fetched, has no failing findings and
+// reaches --min-score.
+func (r validateResult) Passed() bool { return r.Err == nil && len(r.Failed) == 0 && !r.LowScore }
+
+// ciResult converts r for a CI report. A score below --min-score fails the
+// URL, so it is reported as a failing min-score finding.
+func (r validateResult) ciResult(minScore int) ciResult {
+	c := ciResult{URL: r.URL, Err: r.Err, Findings: append(slices.Clip(r.Findings), r.A11y...), Failed: r.Failed}
+	if r.LowScore {
+		low := finding{Rule: "min-score", Severity: sevError, Message: fmt.Sprintf("score %d is below --min-score %d", r.Score.Total, minScore)}
+		c.Findings = append(c.Findings, low)
+		c.Failed = append(slices.Clip(c.Failed), low)
+	}
+	return c
+}
+
+// print renders the findings of one URL as plain validate always has.
+func (r validateResult) print() {
+	if r.Err != nil {
+		color.Red("Error fetching %s: %v", r.URL, r.Err)
+		return
+	}
+	if r.timing != nil {
+		printTiming(r.timing)
+	}
+	if hasRule(r.Findings, "client-side-tags") {
+		printSPA(r.spa)
+	}
+	printWarnings(r.Findings)
+	printCategory("Accessibility", r.A11y)
+	if r.snapshot != nil {
+		printSnapshot(r.snapshot)
+	}
+	printMissing(r.Findings)
+	printSuggestion(r.suggest)
+	printScore(r.Score)
+	if r.Suppressed > 0 {
+		color.New(color.FgHiBlack).Printf("%d finding(s) suppressed\n", r.Suppressed)
+	}
+}
+
+// printValidateSummary lists which URLs passed and failed with their scores,
+// groups the failures by rule, prints the average score per site and returns
+// the number of failed URLs.
+func printValidateSummary(rs []validateResult) int {
+	byRule := make(map[string][]string)
+	severity := make(map[string]string)
+	failed := 0
+	for _, r := range rs {
+		if r.Passed() {
+			continue
+		}
+		failed++
+		if r.Err != nil {
+			byRule["fetch-error"] = append(byRule["fetch-error"], r.URL)
+			severity["fetch-error"] = sevError
+		}
+		if r.LowScore {
+			byRule["min-score"] = append(byRule["min-score"], r.URL)
+			severity["min-score"] = sevError
+		}
+		seen := make(map[string]bool)
+		for _, f := range r.Failed {
+			if !seen[f.Rule] {
+				seen[f.Rule] = true
+				byRule[f.Rule] = append(byRule[f.Rule], r.URL)
+				severity[f.Rule] = f.Severity
+			}
+		}
+	}
+
+	bold := color.New(color.Bold)
+	dim := color.New(color.FgHiBlack)
+	bold.Printf("\nSummary: %d passed, %d failed (%d URLs)\n", len(rs)-failed, failed, len(rs))
+	scores := make(map[string]int, len(rs))
+	for _, r := range rs {
+		if r.Err != nil {
+			color.New(color.FgRed).Printf("  ✘ %s", r.URL)
+			dim.Println(" (fetch failed)")
+			continue
+		}
+		scores[r.URL] = r.Score.Total
+		if r.Passed() {
+			color.New(color.FgGreen).Printf("  ✔ %s", r.URL)
+		} else {
+			color.New(color.FgRed).Printf("  ✘ %s", r.URL)
+		}
+		dim.Printf(" (score %d", r.Score.Total)
+		if len(r.Failed) > 0 {
+			dim.Printf(", %d finding(s)", len(r.Failed))
+		}
+		dim.Println(")")
+	}
+	if len(scores) > 0 {
+		printSiteAverages(scores)
+	}
+	if failed == 0 {
+		return 0
+	}
+
+	rules := make([]string, 0, len(byRule))
+	for id := range byRule {
+		rules = append(rules, id)
+	}
+	// Most widespread first, then by severity and ID.
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if len(byRule[a]) != len(byRule[b]) {
+			return len(byRule[a]) > len(byRule[b])
+		}
+		if severityRank(severity[a]) != severityRank(severity[b]) {
+			return severityRank(severity[a]) > severityRank(severity[b])
+		}
+		return a < b
+	})
+	bold.Println("\nFailures by rule:")
+	for _, id := range rules {
+		fmt.Printf("  %s", id)
+		dim.Printf(" (%s) %d URL(s)\n", severity[id], len(byRule[id]))
+		for _, u := range byRule[id] {
+			dim.Printf("    %s\n", u)
+		}
+	}
+	return failed
+}
+
 func newValidateCmd() *cobra.Command {
 	var essentialsOnly bool
 	var timeout int
 	var semantic bool
+	var showTiming bool
+	var budget crawlerBudget
+	var linkWorkers int
+	var a11yPixels bool
+	var rulesFile string
+	var failOn string
+	var workers int
+	var format string
+	var output string
+	var minScore int
+	var against string
+	var update bool
+	var suggest string
 
 	c := &cobra.Command{
-		Use:   "validate URL",
-		Short: "Exit with status 1 if required OG tags are missing",
-		Args:  cobra.ExactArgs(1),
+		Use:   "validate URL [URL...]",
+		Short: "Exit with status 1 if required OG tags are missing or findings reach --fail-on (use “-” to read URLs from STDIN)",
+		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-			defer cancel()
-
-			html, err := fetchHTML(ctx, args[0])
+			threshold, err := parseFailOn(failOn)
 			if err != nil {
 				return err
 			}
-			og := parseOG(html)
-			if semantic {
-				warns := semanticValidate(og)
-				for _, w := range warns {
-					color.New(color.FgYellow).Printf("⚠ %s\n", w)
+			if err := checkFormat(format); err != nil {
+				return err
+			}
+			if update && against == "" {
+				return errors.New("--update needs --against DIR")
+			}
+			if suggest != "" {
+				if err := checkSuggestFlavor(suggest); err != nil {
+					return err
 				}
 			}
+			// A report on STDOUT replaces the terminal output.
+			quiet := format != "text" && output == ""
+			urls, err := readURLs(args)
+			if err != nil {
+				return err
+			}
+			rules, err := loadRules(rulesFile)
+			if err != nil {
+				return err
+			}
 
-			if code := printMissing(og, essentialsOnly); code != 0 {
-				return errors.New("required tags are missing")
+			results := runPool(urls, workers, func(u string) validateResult {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				p, err := fetchPage(ctx, u)
+				cancel()
+				if err != nil {
+					return validateResult{URL: u, Err: err}
+				}
+				og := parseOG(p.HTML)
+				tw := parseTwitter(p.HTML)
+				meta := parseMeta(p.HTML, "")
+				warns := rules.check(meta, essentialsOnly, semantic)
+				warns = append(warns, ogTypeFindings(meta, parseNamespaces(p.HTML))...)
+				src := cardSources{pageURL: u, og: og, twitter: tw, fallback: parseFallbacks(p.HTML, p.FinalURL)}
+				warns = append(warns, fitFindings(textFits(src))...)
+				warns = append(warns, articleFindings(p.HTML, og, time.Now())...)
+				warns = append(warns, budgetWarnings("page", p.Timing, budget)...)
+				r := validateResult{URL: u}
+				if against != "" {
+					sc := checkSnapshot(against, u, og, update)
+					r.snapshot = &sc
+					warns = append(warns, sc.Findings...)
+				}
+				if showTiming {
+					r.timing = &timingReport{Page: p.Timing, Images: make(map[string]timings)}
+					for _, img := range imageURLs(og, p.FinalURL) {
+						ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+						t, err := timeFetch(ctx, img)
+						cancel()
+						if err != nil {
+							warns = append(warns, warnf("image-unreachable", "cannot fetch og:image %s: %v", img, err))
+							continue
+						}
+						r.timing.Images[img] = t
+						warns = append(warns, budgetWarnings("og:image "+img, t, budget)...)
+					}
+				}
+				pageURLs := newURLReport(u, p, og)
+				ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				warns = append(warns, canonicalFindings(ctx, pageURLs, semantic)...)
+				cancel()
+				ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				warns = append(warns, localeFindings(ctx, p.HTML, pageURLs, semantic, linkWorkers)...)
+				cancel()
+				if semantic {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					warns = append(warns, semanticValidate(ctx, og, p.FinalURL)...)
+					cancel()
+					ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					warns = append(warns, mediaWarnings(ctx, og, tw, p.FinalURL)...)
+					cancel()
+					ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					links := checkLinks(ctx, linkTargets(p.HTML), linkWorkers)
+					cancel()
+					warns = append(warns, linkWarnings(links)...)
+				}
+				if spa := detectSPA(p.HTML, og); spa != nil && spa.Likely {
+					r.spa = spa
+					warns = append(warns, spa.finding())
+				}
+
+				a11y := altTextFindings(imageGroups(p.HTML), og["title"], tw["title"])
+				if img := og["image"]; a11yPixels && img != "" {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					px, err := pixelFindings(ctx, resolveURL(u, img))
+					cancel()
+					if err != nil {
+						px = []finding{warnf("a11y-pixels", "%s", err)}
+					}
+					a11y = append(a11y, px...)
+				}
+
+				var hidden, hiddenA11y int
+				r.Findings, hidden = rules.suppress(u, p.HTML, warns)
+				r.A11y, hiddenA11y = rules.suppress(u, p.HTML, a11y)
+				r.Suppressed = hidden + hiddenA11y
+				all := append(slices.Clip(r.Findings), r.A11y...)
+				r.Failed = failing(all, threshold)
+				r.Score = computeScore(all)
+				if suggest != "" {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+					r.suggest = suggestSnippet(ctx, r.Findings, p.HTML, pageURLs.Final, src.fallback, suggest)
+					cancel()
+				}
+				r.LowScore = r.Score.Total < minScore
+				return r
+			})
+
+			var done []validateResult
+			for r := range results {
+				switch {
+				case quiet:
+				case len(urls) > 1:
+					color.New(color.FgMagenta, color.Bold).Printf("\n[%s]\n", r.URL)
+					r.print()
+				case r.Err == nil:
+					r.print()
+				}
+				done = append(done, r)
+			}
+			order := make(map[string]int, len(urls))
+			for i := len(urls) - 1; i >= 0; i-- {
+				order[urls[i]] = i
+			}
+			sort.SliceStable(done, func(i, j int) bool { return order[done[i].URL] < order[done[j].URL] })
+			if format != "text" {
+				ci := make([]ciResult, 0, len(done))
+				for _, r := range done {
+					ci = append(ci, r.ciResult(minScore))
+				}
+				if err := writeCIReportFile(output, format, "validate", ci); err != nil {
+					return err
+				}
+			}
+
+			if len(urls) == 1 {
+				switch r := done[0]; {
+				case r.Err != nil:
+					return r.Err
+				case len(r.Failed) > 0:
+					return fmt.Errorf("%d finding(s) at or above --fail-on %s", len(r.Failed), failOn)
+				case r.LowScore:
+					return fmt.Errorf("score %d is below --min-score %d", r.Score.Total, minScore)
+				}
+				return nil
+			}
+			failed := 0
+			for _, r := range done {
+				if !r.Passed() {
+					failed++
+				}
+			}
+			if !quiet {
+				printValidateSummary(done)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d URL(s) failed validation", failed, len(done))
 			}
 			return nil
 		},
 	}
 
 	c.Flags().BoolVarP(&essentialsOnly, "essentials", "e", false, "Validate only essential tags (title, type, image, url, description)")
+	c.Flags().StringVar(&rulesFile, "rules", "", "Check properties against this JSON rules file instead of the built-in rules")
+	c.Flags().StringVar(&failOn, "fail-on", sevError, "Exit non-zero on findings of this severity or higher: error, warning, info or none")
 	c.Flags().IntVarP(&timeout, "timeout", "t", int(defaultTimeout.Seconds()), "HTTP timeout in seconds")
 	c.Flags().BoolVarP(&semantic, "semantic", "s", false, "Enable advanced semantic validation")
+	c.Flags().IntVarP(&workers, "workers", "w", runtime.NumCPU(), "Number of URLs validated concurrently")
+	c.Flags().StringVar(&format, "format", "text", "Report format: text, junit, sarif, tap or github")
+	c.Flags().IntVar(&minScore, "min-score", 0, "Exit non-zero when a URL scores below this (0–100)")
+	c.Flags().StringVar(&against, "against", "", "Fail when the live OG differs from the snapshots in this directory (see `ogspy snapshot`)")
+	c.Flags().StringVar(&suggest, "suggest", "", "Print ready-to-paste meta tags for missing or invalid properties: html (default), jsx or gotmpl")
+	c.Flags().Lookup("suggest").NoOptDefVal = "html"
+	c.Flags().BoolVar(&update, "update", false, "With --against, record the live OG as the new snapshot instead of failing")
+	c.Flags().StringVarP(&output, "output", "o", "", "Write the --format report to this file instead of STDOUT")
+	c.Flags().BoolVar(&showTiming, "timing", false, "Show DNS/connect/TLS/TTFB/download timings for the page and each og:image")
+	c.Flags().IntVar(&linkWorkers, "link-workers", 4, "Maximum concurrent link-check requests during --semantic")
+	c.Flags().BoolVar(&a11yPixels, "a11y-pixels", false, "Decode og:image and flag low contrast or baked-in text")
+	budget.addFlags(c)
 	return c
 }
 
@@ -526,6 +1267,8 @@ func newMonitorCmd() *cobra.Command {
 	var timeout int
 	var jsonDiff bool
 	var unified bool
+	var imageHash bool
+	var hashThreshold int
 
 	c := &cobra.Command{
 		Use:   "monitor URL",
@@ -537,8 +1280,9 @@ func newMonitorCmd() *cobra.Command {
 			defer ticker.Stop()
 
 			type event struct {
-				ts string
-				og map[string]string
+				ts    string
+				og    map[string]string
+				image *imageFingerprint
 			}
 			diffChan := make(chan event)
 
@@ -558,16 +1302,26 @@ func newMonitorCmd() *cobra.Command {
 					case <-ticker.C:
 						go func(p map[string]string) {
 							fetchCtx, cancelFetch := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-							html, err := fetchHTML(fetchCtx, url)
+							page, err := fetchPage(fetchCtx, url)
 							cancelFetch()
 							if err != nil {
 								color.Red("Error: %v", err)
 								return
 							}
-							og := parseOG(html)
+							og := parseOG(page.HTML)
+							var fp *imageFingerprint
+							if img := strings.TrimSpace(og["image"]); imageHash && img != "" {
+								imgCtx, cancelImg := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+								fp, err = fingerprintImage(imgCtx, resolveURL(page.FinalURL, img))
+								cancelImg()
+								if err != nil {
+									color.Red("Error: %v", err)
+								}
+							}
 							diffChan <- event{
-								ts: time.Now().UTC().Format(time.RFC3339),
-								og: og,
+								ts:    time.Now().UTC().Format(time.RFC3339),
+								og:    og,
+								image: fp,
 							}
 						}(prev)
 						// prev is updated once the event is processed in main goroutine
@@ -577,19 +1331,31 @@ func newMonitorCmd() *cobra.Command {
 
 			// Render loop (non‑blocking)
 			var prev map[string]string
+			var prevImage *imageFingerprint
 			for ev := range diffChan {
 				diff := diffMaps(prev, ev.og)
-				if len(diff) > 0 {
+				imgChange := compareFingerprints(prevImage, ev.image, hashThreshold)
+				if len(diff) > 0 || imgChange != nil {
 					switch {
 					case jsonDiff:
 						payload := map[string]interface{}{"timestamp": ev.ts, "diff": diff}
+						if imgChange != nil {
+							payload["image_content"] = imgChange
+						}
 						enc := json.NewEncoder(os.Stdout)
 						enc.SetIndent("", "  ")
 						_ = enc.Encode(payload)
 					case unified:
 						printUnified(diff)
+						if imgChange != nil {
+							fmt.Printf("@@ og:image (content) @@\n- sha256:%s\n+ sha256:%s\n", imgChange.Old, imgChange.New)
+						}
 					default:
-						color.New(color.FgYellow, color.Bold).Printf("\n🕒 %s – %d change(s) detected\n", ev.ts, len(diff))
+						changes := len(diff)
+						if imgChange != nil {
+							changes++
+						}
+						color.New(color.FgYellow, color.Bold).Printf("\n🕒 %s – %d change(s) detected\n", ev.ts, changes)
 						for k, v := range diff {
 							color.New(color.FgCyan, color.Bold).Printf("og:%s", k)
 							fmt.Print(" ")
@@ -598,9 +1364,15 @@ func newMonitorCmd() *cobra.Command {
 							color.Green(v[1])
 							fmt.Println()
 						}
+						if imgChange != nil {
+							printImageChange(imgChange)
+						}
 					}
 				}
 				prev = ev.og
+				if ev.image != nil || ev.og["image"] == "" {
+					prevImage = ev.image
+				}
 			}
 			return nil
 		},
@@ -610,6 +1382,8 @@ func newMonitorCmd() *cobra.Command {
 	c.Flags().IntVarP(&timeout, "timeout", "t", int(defaultTimeout.Seconds()), "HTTP timeout in seconds")
 	c.Flags().BoolVarP(&jsonDiff, "json-diff", "j", false, "Print the diff as JSON instead of coloured text")
 	c.Flags().BoolVarP(&unified, "unified", "u", false, "Print diff in unified format")
+	c.Flags().BoolVar(&imageHash, "image-hash", false, "Fingerprint og:image (SHA-256 + perceptual hash) to detect picture swaps behind an unchanged URL")
+	c.Flags().IntVar(&hashThreshold, "hash-threshold", 10, "Perceptual hash distance (0–64) above which og:image counts as visually changed")
 	return c
 }
 
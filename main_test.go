@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -62,10 +66,29 @@ func TestSemanticValidate(t *testing.T) {
 		"type":  "article",
 		"image": "http://insecure/img.jpg",
 	}
-	warns := semanticValidate(og)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	warns := semanticValidate(ctx, og, "https://example.com/post")
 	if len(warns) == 0 {
 		t.Fatal("semanticValidate: expected warnings, got none")
 	}
+
+	// A relative og:image is probed at its URL on the page's host.
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1200, 630)))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/img/og.png" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+	for _, w := range semanticValidate(ctx, map[string]string{"image": "/img/og.png"}, srv.URL+"/post/1") {
+		if w.Rule == "image-unreachable" {
+			t.Errorf("relative og:image: %s", w.Message)
+		}
+	}
 }
 
 // ----------------------------------------------------------------------------
@@ -90,3 +113,36 @@ func TestFetchHTML(t *testing.T) {
 		t.Errorf("fetchHTML output mismatch")
 	}
 }
+
+func TestRunPool(t *testing.T) {
+	urls := []string{"a", "b", "c", "d", "e"}
+	got := map[string]bool{}
+	for r := range runPool(urls, 3, strings.ToUpper) {
+		got[r] = true
+	}
+	if len(got) != len(urls) || !got["E"] {
+		t.Errorf("runPool results = %v", got)
+	}
+}
+
+func TestValidateSummary(t *testing.T) {
+	fail := warnf("og-image-https", "og:image should use HTTPS")
+	rs := []validateResult{
+		{URL: "https://a.example/"},
+		{URL: "https://b.example/", Findings: []finding{fail}, Failed: []finding{fail}},
+		{URL: "https://c.example/", Err: context.DeadlineExceeded},
+		{URL: "https://d.example/", Findings: []finding{fail}},
+	}
+	if n := printValidateSummary(rs); n != 2 {
+		t.Errorf("printValidateSummary = %d failed, want 2", n)
+	}
+	if !rs[0].Passed() || !rs[3].Passed() || rs[1].Passed() || rs[2].Passed() {
+		t.Error("Passed() disagrees with Err/Failed")
+	}
+
+	low := validateResult{URL: "https://e.example/", Findings: []finding{fail}, Score: score{Total: 40}, LowScore: true}
+	c := low.ciResult(80)
+	if len(c.Failed) != 1 || c.Failed[0].Rule != "min-score" || !slices.Contains(c.Findings, c.Failed[0]) {
+		t.Errorf("ciResult of a low score: Failed = %v, Findings = %v, want a failing min-score finding", c.Failed, c.Findings)
+	}
+}
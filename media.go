@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Video, Audio & Player Validation
+// ------------------------------------------------------------------------------------------------
+
+// mediaProbe is what a HEAD (or one-byte GET) reveals about a media URL.
+type mediaProbe struct {
+	Status      int
+	ContentType string // without parameters
+	Header      http.Header
+}
+
+// playerEmbedders lists the origins that frame each kind of player.
+var playerEmbedders = map[string][]string{
+	"og:video":       {"www.facebook.com", "facebook.com"},
+	"twitter:player": {"twitter.com", "x.com", "platform.twitter.com"},
+}
+
+// mediaWarnings validates og:video, og:audio and twitter:player: HTTPS,
+// reachability, declared versus served MIME type, declared dimensions and,
+// for HTML players, whether the embedding platform is allowed to frame them.
+// URLs are resolved against the page URL base and probed within ctx.
+func mediaWarnings(ctx context.Context, og, tw map[string]string, base string) []finding {
+	var warns []finding
+	warns = append(warns, ogMediaWarnings(ctx, "video", og, base)...)
+	warns = append(warns, ogMediaWarnings(ctx, "audio", og, base)...)
+
+	if player := strings.TrimSpace(tw["player"]); player != "" {
+		player = resolveURL(base, player)
+		if !strings.HasPrefix(player, "https://") {
+			warns = append(warns, warnf("media-https", "twitter:player must use HTTPS"))
+		}
+		for _, dim := range []string{"player:width", "player:height"} {
+			if w := dimensionWarning("twitter:"+dim, tw[dim]); w != "" {
+				warns = append(warns, warnf("media-dimensions", "%s", w))
+			}
+		}
+		if p, err := probeMedia(ctx, player, true); err != nil {
+			warns = append(warns, warnf("media-unreachable", "twitter:player %s", err))
+		} else {
+			if p.Status >= http.StatusBadRequest {
+				warns = append(warns, warnf("media-unreachable", "twitter:player returned HTTP %d", p.Status))
+			}
+			if w := framingWarning("twitter:player", p.Header, playerEmbedders["twitter:player"]); w != "" {
+				warns = append(warns, warnf("media-framing", "%s", w))
+			}
+		}
+	}
+	return warns
+}
+
+// ogMediaWarnings validates one og:video or og:audio structure.
+func ogMediaWarnings(ctx context.Context, kind string, og map[string]string, base string) []finding {
+	src := strings.TrimSpace(og[kind])
+	if src == "" {
+		src = strings.TrimSpace(og[kind+":url"])
+	}
+	secure := strings.TrimSpace(og[kind+":secure_url"])
+	if src == "" && secure == "" {
+		return nil
+	}
+	if src != "" {
+		src = resolveURL(base, src)
+	}
+	if secure != "" {
+		secure = resolveURL(base, secure)
+	}
+	var warns []finding
+	label := "og:" + kind
+
+	if secure != "" && !strings.HasPrefix(secure, "https://") {
+		warns = append(warns, warnf("media-https", "%s:secure_url must use HTTPS", label))
+	}
+	if secure == "" && !strings.HasPrefix(src, "https://") {
+		warns = append(warns, warnf("media-https", "%s should use HTTPS or provide %s:secure_url", label, label))
+	}
+	declared := strings.ToLower(strings.TrimSpace(og[kind+":type"]))
+	if declared == "" {
+		warns = append(warns, warnf("media-type-missing", "%s:type is missing", label))
+	}
+	if kind == "video" {
+		for _, dim := range []string{"video:width", "video:height"} {
+			if w := dimensionWarning("og:"+dim, og[dim]); w != "" {
+				warns = append(warns, warnf("media-dimensions", "%s", w))
+			}
+		}
+	}
+
+	target := secure
+	if target == "" {
+		target = src
+	}
+	isPlayer := declared == "text/html"
+	p, err := probeMedia(ctx, target, isPlayer)
+	if err != nil {
+		return append(warns, warnf("media-unreachable", "%s %s", label, err))
+	}
+	if p.Status >= http.StatusBadRequest {
+		return append(warns, warnf("media-unreachable", "%s returned HTTP %d", label, p.Status))
+	}
+	if declared != "" && p.ContentType != "" && declared != p.ContentType {
+		warns = append(warns, warnf("media-type-mismatch", "%s:type declares %s but the server sends %s", label, declared, p.ContentType))
+	}
+	if isPlayer || p.ContentType == "text/html" {
+		if w := framingWarning(label, p.Header, playerEmbedders["og:video"]); w != "" {
+			warns = append(warns, warnf("media-framing", "%s", w))
+		}
+	}
+	return warns
+}
+
+// dimensionWarning checks that a declared width/height is present and a
+// positive integer.
+func dimensionWarning(key, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return key + " is missing"
+	}
+	if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+		return fmt.Sprintf("%s %q is not a positive integer", key, value)
+	}
+	return ""
+}
+
+// probeMedia checks a media URL without downloading it: a HEAD request, or a
+// one-byte ranged GET when the server rejects HEAD. Players always use GET
+// because framing headers are frequently omitted from HEAD responses.
+func probeMedia(ctx context.Context, rawURL string, player bool) (*mediaProbe, error) {
+	do := func(method string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		if method == http.MethodGet {
+			req.Header.Set("Range", "bytes=0-0")
+		}
+		return httpClient.Do(req)
+	}
+
+	method := http.MethodHead
+	if player {
+		method = http.MethodGet
+	}
+	resp, err := do(method)
+	if err == nil && method == http.MethodHead && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = do(http.MethodGet)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("is unreachable: %w", err)
+	}
+	resp.Body.Close()
+
+	ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return &mediaProbe{Status: resp.StatusCode, ContentType: strings.ToLower(ct), Header: resp.Header}, nil
+}
+
+// framingWarning reports why the embedders cannot frame a player, based on
+// CSP frame-ancestors (which takes precedence) or X-Frame-Options.
+func framingWarning(label string, h http.Header, embedders []string) string {
+	// Every policy must admit the embedder; any frame-ancestors directive
+	// makes browsers ignore X-Frame-Options.
+	sawCSP := false
+	for _, policy := range strings.Split(strings.Join(h.Values("Content-Security-Policy"), ","), ",") {
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(strings.TrimSpace(directive))
+			if len(fields) == 0 || !strings.EqualFold(fields[0], "frame-ancestors") {
+				continue
+			}
+			sawCSP = true
+			if !ancestorsAllow(fields[1:], embedders) {
+				return fmt.Sprintf("%s cannot be embedded: CSP frame-ancestors %q excludes %s", label, strings.Join(fields[1:], " "), embedders[0])
+			}
+		}
+	}
+	if sawCSP {
+		return ""
+	}
+	switch xfo := strings.ToUpper(strings.TrimSpace(h.Get("X-Frame-Options"))); {
+	case xfo == "DENY", xfo == "SAMEORIGIN", strings.HasPrefix(xfo, "ALLOW-FROM"):
+		return fmt.Sprintf("%s cannot be embedded: X-Frame-Options is %s", label, xfo)
+	}
+	return ""
+}
+
+// ancestorsAllow reports whether a frame-ancestors source list admits any of
+// the embedder hosts.
+func ancestorsAllow(sources, embedders []string) bool {
+	for _, src := range sources {
+		src = strings.ToLower(src)
+		switch src {
+		case "*", "https:":
+			return true
+		case "'none'", "'self'":
+			continue
+		}
+		host := src
+		if u, err := url.Parse(src); err == nil && u.Host != "" {
+			host = u.Hostname()
+		}
+		for _, e := range embedders {
+			if host == e || strings.HasPrefix(host, "*.") && strings.HasSuffix(e, host[1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFramingWarning(t *testing.T) {
+	embedders := playerEmbedders["twitter:player"]
+	cases := []struct {
+		name   string
+		header http.Header
+		ok     bool
+	}{
+		{"no headers", http.Header{}, true},
+		{"xfo deny", http.Header{"X-Frame-Options": {"DENY"}}, false},
+		{"csp none", http.Header{"Content-Security-Policy": {"default-src 'self'; frame-ancestors 'none'"}}, false},
+		{"csp allows x.com", http.Header{"Content-Security-Policy": {"frame-ancestors 'self' https://x.com"}}, true},
+		{"csp overrides xfo", http.Header{"X-Frame-Options": {"SAMEORIGIN"}, "Content-Security-Policy": {"frame-ancestors *.twitter.com"}}, true},
+	}
+	for _, c := range cases {
+		if got := framingWarning("twitter:player", c.header, embedders) == ""; got != c.ok {
+			t.Errorf("%s: framable = %v, want %v", c.name, got, c.ok)
+		}
+	}
+}
+
+func TestMediaWarnings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/clip.mp4":
+			w.Header().Set("Content-Type", "video/webm")
+		case "/player":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	og := map[string]string{
+		"video":        srv.URL + "/clip.mp4",
+		"video:type":   "video/mp4",
+		"video:width":  "1280",
+		"video:height": "720",
+		"audio":        "/missing.mp3", // relative to the page
+		"audio:type":   "audio/mpeg",
+	}
+	tw := map[string]string{"player": srv.URL + "/player", "player:width": "480"}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	warns := strings.Join(messages(mediaWarnings(ctx, og, tw, srv.URL+"/page")), "\n")
+
+	for _, want := range []string{
+		"og:video should use HTTPS",
+		"og:video:type declares video/mp4 but the server sends video/webm",
+		"og:audio returned HTTP 404",
+		"twitter:player must use HTTPS",
+		"twitter:player:height is missing",
+		"twitter:player cannot be embedded: X-Frame-Options is SAMEORIGIN",
+	} {
+		if !strings.Contains(warns, want) {
+			t.Errorf("mediaWarnings: missing %q in\n%s", want, warns)
+		}
+	}
+}
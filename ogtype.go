@@ -0,0 +1,138 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ------------------------------------------------------------------------------------------------
+// og:type Vocabulary & Namespaces
+// ------------------------------------------------------------------------------------------------
+
+// ogTypes is the og:type vocabulary of the Open Graph protocol. Anything else
+// must be a "prefix:type" in a namespace the page declares.
+var ogTypes = []string{
+	"website", "article", "book", "profile",
+	"music.song", "music.album", "music.playlist", "music.radio_station",
+	"video.movie", "video.episode", "video.tv_show", "video.other",
+}
+
+// standardNamespaces maps the Open Graph prefixes to their namespace URIs.
+var standardNamespaces = map[string]string{
+	"og":      "https://ogp.me/ns#",
+	"fb":      "https://ogp.me/ns/fb#",
+	"article": "https://ogp.me/ns/article#",
+	"book":    "https://ogp.me/ns/book#",
+	"profile": "https://ogp.me/ns/profile#",
+	"music":   "https://ogp.me/ns/music#",
+	"video":   "https://ogp.me/ns/video#",
+	"website": "https://ogp.me/ns/website#",
+}
+
+// parseNamespaces reads the RDFa prefix declarations ("myapp: https://…")
+// from the prefix attribute of <html> and <head>; <head> wins on conflict.
+func parseNamespaces(html string) map[string]string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	ns := make(map[string]string)
+	doc.Find("html, head").Each(func(_ int, s *goquery.Selection) {
+		attr, _ := s.Attr("prefix")
+		fields := strings.Fields(attr)
+		for i := 0; i+1 < len(fields); i++ {
+			if p, ok := strings.CutSuffix(fields[i], ":"); ok && p != "" {
+				ns[strings.ToLower(p)] = fields[i+1]
+				i++
+			}
+		}
+	})
+	return ns
+}
+
+// customPrefixes returns the declared prefixes outside the Open Graph set,
+// sorted.
+func customPrefixes(ns map[string]string) []string {
+	var out []string
+	for p := range ns {
+		if _, ok := standardNamespaces[p]; !ok {
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// customProperties returns the meta properties in the page's custom
+// namespaces, keyed by full property name.
+func customProperties(meta map[string]string, ns map[string]string) map[string]string {
+	prefixes := customPrefixes(ns)
+	out := make(map[string]string)
+	for k, v := range meta {
+		if p, _, ok := strings.Cut(k, ":"); ok && slices.Contains(prefixes, p) {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// typeNamespace returns the object namespace whose properties belong to
+// ogType: "music" for music.song, "book" for book.
+func typeNamespace(ogType string) string {
+	ns, _, _ := strings.Cut(ogType, ".")
+	return ns
+}
+
+// ogTypeFindings checks og:type against the vocabulary and the declared
+// namespaces, and flags object properties (music:*, book:*, …) that do not
+// belong to the declared type. meta is keyed by full property name.
+func ogTypeFindings(meta map[string]string, ns map[string]string) []finding {
+	ogType := strings.TrimSpace(meta["og:type"])
+	if ogType == "" {
+		return nil
+	}
+	var out []finding
+	if prefix, _, ok := strings.Cut(ogType, ":"); ok {
+		if _, declared := ns[strings.ToLower(prefix)]; !declared {
+			out = append(out, warnf("og-type-namespace", "og:type %q uses the prefix %s: without declaring it in <html prefix> or <head prefix>", ogType, prefix))
+		}
+		return out
+	}
+	if !slices.Contains(ogTypes, ogType) {
+		hint := ""
+		for _, t := range ogTypes {
+			if strings.EqualFold(t, ogType) || strings.EqualFold(strings.ReplaceAll(t, ".", "_"), ogType) || strings.HasSuffix(t, "."+strings.ToLower(ogType)) {
+				hint = "; did you mean " + t + "?"
+				break
+			}
+		}
+		return append(out, warnf("og-type-unknown", "og:type %q is not an Open Graph type%s", ogType, hint))
+	}
+
+	foreign := map[string]bool{}
+	for k := range meta {
+		prefix, _, ok := strings.Cut(k, ":")
+		if !ok || prefix == typeNamespace(ogType) {
+			continue
+		}
+		switch prefix {
+		case "article", "book", "profile", "music", "video":
+			foreign[prefix] = true
+		}
+	}
+	keys := make([]string, 0, len(foreign))
+	for p := range foreign {
+		keys = append(keys, p+":*")
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out = append(out, infof("og-type-foreign-properties", "%s properties are ignored on og:type %s", k, ogType))
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	html := `<html prefix="og: https://ogp.me/ns# recipe: https://example.com/ns/recipe#">
+	<head prefix="music: https://ogp.me/ns/music#  Shop: https://example.com/ns/shop#">
+	<meta property="recipe:yield" content="4">
+	<meta property="shop:product" content="https://example.com/p/1">
+	<meta property="music:song" content="https://example.com/s/1">
+	</head></html>`
+	ns := parseNamespaces(html)
+	if len(ns) != 4 || ns["shop"] != "https://example.com/ns/shop#" {
+		t.Fatalf("parseNamespaces = %v", ns)
+	}
+	if got := customPrefixes(ns); !slices.Equal(got, []string{"recipe", "shop"}) {
+		t.Errorf("customPrefixes = %v", got)
+	}
+	custom := customProperties(parseMeta(html, ""), ns)
+	if len(custom) != 2 || custom["recipe:yield"] != "4" {
+		t.Errorf("customProperties = %v", custom)
+	}
+	var props []string
+	for _, l := range linkTargets(html) {
+		props = append(props, l.Property)
+	}
+	if !slices.Equal(props, []string{"shop:product", "music:song"}) {
+		t.Errorf("linkTargets properties = %v", props)
+	}
+}
+
+func TestOGTypeFindings(t *testing.T) {
+	ns := map[string]string{"recipe": "https://example.com/ns/recipe#"}
+	for _, c := range []struct {
+		meta map[string]string
+		want string
+	}{
+		{map[string]string{"og:type": "music.song", "music:duration": "200"}, ""},
+		{map[string]string{"og:type": "recipe:dish"}, ""},
+		{map[string]string{"og:type": "shop:product"}, "og-type-namespace"},
+		{map[string]string{"og:type": "blog"}, "og-type-unknown"},
+		{map[string]string{"og:type": "movie"}, "og-type-unknown"},
+		{map[string]string{"og:type": "website", "book:isbn": "x", "book:author": "y", "og:video:url": "z"}, "og-type-foreign-properties"},
+	} {
+		if got := ruleIDs(ogTypeFindings(c.meta, ns)); got != c.want {
+			t.Errorf("ogTypeFindings(%v) = %q, want %q", c.meta, got, c.want)
+		}
+	}
+	if fs := ogTypeFindings(map[string]string{"og:type": "movie"}, nil); len(fs) != 1 || fs[0].Message != `og:type "movie" is not an Open Graph type; did you mean video.movie?` {
+		t.Errorf("hint: %v", messages(fs))
+	}
+}
+
+// schemaRuleIDs runs the built-in rules in --semantic mode and keeps the
+// findings outside the og: and article: namespaces.
+func schemaRuleIDs(meta map[string]string) string {
+	var fs []finding
+	for _, f := range builtinRules.check(meta, false, true) {
+		if !strings.HasPrefix(f.Property, "og:") && !strings.HasPrefix(f.Property, "article:") {
+			fs = append(fs, f)
+		}
+	}
+	return ruleIDs(fs)
+}
+
+func TestTypeSchemas(t *testing.T) {
+	meta := map[string]string{
+		"og:type":        "music.song",
+		"music:duration": "3:20",
+		"music:musician": "The Band",
+	}
+	if got, want := schemaRuleIDs(meta), "music-duration-pattern music-musician-pattern"; got != want {
+		t.Errorf("music.song rules = %q, want %q", got, want)
+	}
+
+	meta = map[string]string{"og:type": "video.episode", "video:duration": "1200"}
+	if got := schemaRuleIDs(meta); got != "video-series-missing" {
+		t.Errorf("video.episode rules = %q", got)
+	}
+	meta = map[string]string{"og:type": "book", "book:author": "https://example.com/a", "book:isbn": "978-3-16-148410-0"}
+	if got := schemaRuleIDs(meta); got != "" {
+		t.Errorf("valid book: rules = %q", got)
+	}
+	meta["book:isbn"] = "12345"
+	if got := schemaRuleIDs(meta); got != "book-isbn-pattern" {
+		t.Errorf("bad ISBN: rules = %q", got)
+	}
+}
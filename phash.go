@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Image Fingerprinting
+// ------------------------------------------------------------------------------------------------
+
+// imageFingerprint identifies the picture behind an og:image URL so monitor
+// can notice a swap even when the URL stays the same.
+type imageFingerprint struct {
+	URL      string `json:"url"`
+	SHA256   string `json:"sha256"`
+	PHash    string `json:"phash,omitempty"` // 64-bit DCT hash, hex; empty when the format cannot be decoded
+	phash    uint64
+	hasPHash bool
+}
+
+// imageChange describes a content change behind an unchanged og:image URL.
+type imageChange struct {
+	URL      string `json:"url"`
+	Old      string `json:"old_sha256"`
+	New      string `json:"new_sha256"`
+	Distance int    `json:"phash_distance"` // -1 when no perceptual hash is available
+}
+
+// fingerprintImage downloads the image (up to 5 MB) and computes its content
+// hash plus, for formats the standard library decodes and images within
+// maxDecodePixels, its perceptual hash.
+func fingerprintImage(ctx context.Context, imgURL string) (*imageFingerprint, error) {
+	data, _, err := fetchImageBytes(ctx, imgURL)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	fp := &imageFingerprint{URL: imgURL, SHA256: hex.EncodeToString(sum[:])}
+	if img, _, err := decodeImage(data); err == nil {
+		fp.phash, fp.hasPHash = perceptualHash(img), true
+		fp.PHash = fmt.Sprintf("%016x", fp.phash)
+	}
+	return fp, nil
+}
+
+// compareFingerprints reports a content change between two fingerprints of
+// the same URL. Byte-level changes within threshold (re-encoding, metadata
+// stripping) are ignored when both perceptual hashes are known.
+func compareFingerprints(prev, cur *imageFingerprint, threshold int) *imageChange {
+	if prev == nil || cur == nil || prev.URL != cur.URL || prev.SHA256 == cur.SHA256 {
+		return nil
+	}
+	change := &imageChange{URL: cur.URL, Old: prev.SHA256, New: cur.SHA256, Distance: -1}
+	if prev.hasPHash && cur.hasPHash {
+		change.Distance = bits.OnesCount64(prev.phash ^ cur.phash)
+		if change.Distance <= threshold {
+			return nil
+		}
+	}
+	return change
+}
+
+// perceptualHash computes the classic 64-bit pHash: the image is reduced to
+// 32×32 greyscale, transformed with a 2-D DCT, and each of the 8×8 lowest
+// frequencies (DC excluded from the median) becomes one bit set when above
+// the median.
+func perceptualHash(img image.Image) uint64 {
+	const n = 32
+	grey := greyscale(img, n, n)
+
+	// Separable 2-D DCT-II, keeping only the 8×8 low-frequency block.
+	var dct [8][8]float64
+	for u := 0; u < 8; u++ {
+		for v := 0; v < 8; v++ {
+			var sum float64
+			for x := 0; x < n; x++ {
+				cx := math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * n))
+				for y := 0; y < n; y++ {
+					sum += grey[y*n+x] * cx * math.Cos(float64(2*y+1)*float64(v)*math.Pi/(2*n))
+				}
+			}
+			dct[u][v] = sum
+		}
+	}
+
+	coeffs := make([]float64, 0, 63)
+	for u := 0; u < 8; u++ {
+		for v := 0; v < 8; v++ {
+			if u != 0 || v != 0 {
+				coeffs = append(coeffs, dct[u][v])
+			}
+		}
+	}
+	sort.Float64s(coeffs)
+	median := coeffs[len(coeffs)/2]
+
+	var hash uint64
+	for u := 0; u < 8; u++ {
+		for v := 0; v < 8; v++ {
+			if dct[u][v] > median {
+				hash |= 1 << uint(u*8+v)
+			}
+		}
+	}
+	return hash
+}
+
+// greyscale box-samples img down to w×h luma values in [0, 255].
+func greyscale(img image.Image, w, h int) []float64 {
+	b := img.Bounds()
+	out := make([]float64, w*h)
+	for ty := 0; ty < h; ty++ {
+		y0 := b.Min.Y + ty*b.Dy()/h
+		y1 := max(b.Min.Y+(ty+1)*b.Dy()/h, y0+1)
+		for tx := 0; tx < w; tx++ {
+			x0 := b.Min.X + tx*b.Dx()/w
+			x1 := max(b.Min.X+(tx+1)*b.Dx()/w, x0+1)
+			var sum float64
+			var count int
+			for y := y0; y < y1 && y < b.Max.Y; y++ {
+				for x := x0; x < x1 && x < b.Max.X; x++ {
+					r, g, bl, _ := img.At(x, y).RGBA()
+					sum += (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+					count++
+				}
+			}
+			if count > 0 {
+				out[ty*w+tx] = sum / float64(count)
+			}
+		}
+	}
+	return out
+}
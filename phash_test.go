@@ -0,0 +1,61 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math/bits"
+	"testing"
+)
+
+// blocks renders a w×h test picture made of a fixed 6×4 grid of grey
+// levels; flip mirrors it horizontally.
+func blocks(w, h int, flip bool) image.Image {
+	levels := [4][6]uint8{
+		{20, 200, 90, 240, 10, 130},
+		{180, 40, 220, 60, 150, 30},
+		{70, 250, 15, 110, 200, 80},
+		{230, 100, 170, 35, 65, 190},
+	}
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			col := x * 6 / w
+			if flip {
+				col = 5 - col
+			}
+			img.SetGray(x, y, color.Gray{levels[y*4/h][col]})
+		}
+	}
+	return img
+}
+
+func TestPerceptualHash(t *testing.T) {
+	a := perceptualHash(blocks(1200, 630, false))
+	b := perceptualHash(blocks(600, 315, false))
+	c := perceptualHash(blocks(1200, 630, true))
+
+	if d := bits.OnesCount64(a ^ b); d > 4 {
+		t.Errorf("resized copy distance = %d, want ≤ 4", d)
+	}
+	if d := bits.OnesCount64(a ^ c); d <= 10 {
+		t.Errorf("mirrored picture distance = %d, want > 10", d)
+	}
+}
+
+func TestCompareFingerprints(t *testing.T) {
+	prev := &imageFingerprint{URL: "u", SHA256: "aa", phash: 0, hasPHash: true}
+
+	if ch := compareFingerprints(prev, &imageFingerprint{URL: "u", SHA256: "aa"}, 10); ch != nil {
+		t.Error("identical bytes must not be reported")
+	}
+	if ch := compareFingerprints(prev, &imageFingerprint{URL: "u", SHA256: "bb", phash: 0b111, hasPHash: true}, 10); ch != nil {
+		t.Error("re-encoding within threshold must not be reported")
+	}
+	ch := compareFingerprints(prev, &imageFingerprint{URL: "u", SHA256: "bb", phash: ^uint64(0), hasPHash: true}, 10)
+	if ch == nil || ch.Distance != 64 {
+		t.Errorf("visual change not reported: %+v", ch)
+	}
+	if ch := compareFingerprints(prev, &imageFingerprint{URL: "other", SHA256: "bb"}, 10); ch != nil {
+		t.Error("URL changes are reported by the tag diff, not as content changes")
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+// ------------------------------------------------------------------------------------------------
+// Platform Profiles
+// ------------------------------------------------------------------------------------------------
+
+// platform describes how one social network renders a link preview. The
+// numbers are observed desktop behaviour; they drift, so treat them as
+// approximations rather than contracts.
+type platform struct {
+	Key             string  // stable identifier, e.g. "facebook"
+	Name            string  // display name
+	ImageRatio      float64 // width/height of the large-card crop
+	ThumbRatio      float64 // width/height of the small thumbnail crop (0 = none)
+	TitleMax        int     // characters shown before truncation
+	TitleMaxPx      int     // pixels shown before truncation (0 = no width limit)
+	FontPx          float64 // title font size, for the pixel estimate
+	DescMax         int     // characters shown before truncation (0 = hidden)
+	PrefersTwitter  bool    // reads twitter:* before og:*
+	FallbackToPage  bool    // scrapes <title>, meta description and page images when OG is missing
+	UppercaseDomain bool    // renders the domain in capitals above the title
+	MaxImageBytes   int64   // larger images are dropped from the preview (0 = no known limit)
+}
+
+// platforms is the ordered list of profiles used by the report and checks.
+var platforms = []platform{
+	{Key: "facebook", Name: "Facebook", ImageRatio: 1.91, TitleMax: 88, DescMax: 110, FallbackToPage: true, UppercaseDomain: true, MaxImageBytes: 8 << 20},
+	{Key: "x", Name: "X", ImageRatio: 2, TitleMax: 70, TitleMaxPx: 480, FontPx: 15, DescMax: 0, PrefersTwitter: true, MaxImageBytes: 5 << 20},
+	{Key: "linkedin", Name: "LinkedIn", ImageRatio: 1.91, ThumbRatio: 1, TitleMax: 70, TitleMaxPx: 1000, FontPx: 14, DescMax: 0, FallbackToPage: true, MaxImageBytes: 5 << 20},
+	{Key: "slack", Name: "Slack", ImageRatio: 1.91, TitleMax: 150, DescMax: 300, FallbackToPage: true},
+	{Key: "discord", Name: "Discord", ImageRatio: 1.91, TitleMax: 256, DescMax: 350, FallbackToPage: true},
+	{Key: "whatsapp", Name: "WhatsApp", ImageRatio: 1, TitleMax: 65, DescMax: 80, FallbackToPage: true, MaxImageBytes: 300 << 10},
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------------
+// HTML Report
+// ------------------------------------------------------------------------------------------------
+
+// card is one simulated link preview as a platform would render it.
+type card struct {
+	Key         string
+	Platform    string
+	Domain      string
+	Title       string
+	Description string
+	Image       template.URL // data: URI when the image could be embedded
+	Crop        template.CSS // aspect-ratio of the platform's image crop
+	Notes       []string
+}
+
+// reportEntry groups the cards and findings for one URL.
+type reportEntry struct {
+	URL      string
+	Error    string
+	Cards    []card
+	Findings []finding
+}
+
+// cardSources is what a card is built from: OG, Twitter Card and fallback data.
+type cardSources struct {
+	pageURL  string
+	og       map[string]string
+	twitter  map[string]string
+	fallback fallbackData
+}
+
+// pick returns the first non-empty value.
+func pick(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// buildCards renders one card per platform profile.
+func buildCards(src cardSources) []card {
+	cards := make([]card, 0, len(platforms))
+	for _, p := range platforms {
+		cards = append(cards, buildCard(p, src))
+	}
+	return cards
+}
+
+// texts resolves title, description and image the way p does.
+func (p platform) texts(src cardSources) (title, desc, img string) {
+	og, tw, fb := src.og, src.twitter, src.fallback
+	switch {
+	case p.PrefersTwitter:
+		title = pick(tw["title"], og["title"])
+		desc = pick(tw["description"], og["description"])
+		img = pick(tw["image"], tw["image:src"], og["image"])
+	case p.FallbackToPage:
+		title = pick(og["title"], tw["title"], fb.Title)
+		desc = pick(og["description"], tw["description"], fb.Description)
+		img = pick(og["image"], tw["image"], fb.Image)
+	default:
+		title = pick(og["title"], fb.Title)
+		desc = pick(og["description"], fb.Description)
+		img = og["image"]
+	}
+	return title, desc, img
+}
+
+// buildCard resolves title, description, image and domain the way p does and
+// applies its truncation.
+func buildCard(p platform, src cardSources) card {
+	title, desc, img := p.texts(src)
+	c := card{Key: p.Key, Platform: p.Name, Crop: template.CSS(fmt.Sprintf("aspect-ratio:%.2f/1", p.ImageRatio))}
+	if u, err := url.Parse(pick(src.og["url"], src.pageURL)); err == nil {
+		c.Domain = strings.TrimPrefix(u.Hostname(), "www.")
+	}
+	if p.UppercaseDomain {
+		c.Domain = strings.ToUpper(c.Domain)
+	}
+	t := p.fit("title", title)
+	if c.Title = t.Shown; t.Truncated {
+		c.Notes = append(c.Notes, "title truncated at "+t.Limit)
+	}
+	if p.DescMax > 0 {
+		d := p.fit("description", desc)
+		if c.Description = d.Shown; d.Truncated {
+			c.Notes = append(c.Notes, "description truncated at "+d.Limit)
+		}
+	}
+	if title == "" {
+		c.Notes = append(c.Notes, "no title available")
+	}
+	img = resolveURL(src.pageURL, img)
+	switch {
+	case img == "":
+		c.Notes = append(c.Notes, "no image – small or text-only card")
+	case !strings.HasPrefix(img, "http://") && !strings.HasPrefix(img, "https://"):
+		c.Notes = append(c.Notes, "image URL is not http(s) – platforms will ignore it")
+	default:
+		c.Image = template.URL(img)
+	}
+	return c
+}
+
+// embedImages replaces every card image with a data: URI so the report works
+// offline and shows exactly what was fetched at report time.
+func embedImages(cards []card, timeout time.Duration) {
+	cache := make(map[template.URL]template.URL)
+	for i := range cards {
+		src := cards[i].Image
+		if src == "" {
+			continue
+		}
+		if data, ok := cache[src]; ok {
+			cards[i].Image = data
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		raw, ct, err := fetchImageBytes(ctx, string(src))
+		cancel()
+		if err != nil {
+			cards[i].Notes = append(cards[i].Notes, err.Error())
+			cache[src] = src
+			continue
+		}
+		if ct == "" || !strings.HasPrefix(ct, "image/") {
+			if info, err := sniffImage(raw); err == nil {
+				ct = info.MIME
+			}
+		}
+		data := template.URL("data:" + ct + ";base64," + base64.StdEncoding.EncodeToString(raw))
+		cache[src] = data
+		cards[i].Image = data
+	}
+}
+
+// writeHTMLReport renders entries into a single self-contained HTML file.
+func writeHTMLReport(path string, entries []reportEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = reportTmpl.Execute(f, struct {
+		Generated string
+		Version   string
+		Entries   []reportEntry
+	}{time.Now().UTC().Format(time.RFC1123), version, entries})
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// severityIcons mirror the icons of the terminal output (see printFinding).
+var severityIcons = map[string]string{sevError: "✘", sevWarning: "⚠", sevInfo: "ℹ"}
+
+var reportTmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"icon": func(severity string) string { return severityIcons[severity] },
+}).Parse(`<!doctype html>
+<html lang="en"><head><meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>ogspy report</title>
+<style>
+body{font:14px/1.4 -apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif;margin:0;background:#f3f4f6;color:#111}
+header{background:#111827;color:#fff;padding:16px 24px}header small{color:#9ca3af}
+section{margin:24px;padding:16px;background:#fff;border-radius:8px;box-shadow:0 1px 3px rgba(0,0,0,.1)}
+h2{font-size:16px;word-break:break-all;margin:0 0 12px}
+.row{display:flex;gap:24px;align-items:flex-start}.cards{flex:3;display:grid;grid-template-columns:repeat(auto-fill,minmax(300px,1fr));gap:16px}
+.findings{flex:1;min-width:240px}.findings li{margin-bottom:6px}.ok{color:#059669}.err{color:#dc2626}
+.findings .error{color:#dc2626}.findings .warning{color:#b45309}.findings .info{color:#0e7490}.findings small{color:#6b7280}
+.card{border:1px solid #dadde1;border-radius:8px;overflow:hidden;background:#fff}
+.card .label{font-size:11px;font-weight:600;text-transform:uppercase;color:#6b7280;padding:6px 10px;background:#f9fafb;border-bottom:1px solid #e5e7eb}
+.card img{display:block;width:100%;object-fit:cover;background:#e5e7eb}
+.card .body{padding:8px 12px}.card .domain{font-size:12px;color:#65676b}.card .title{font-weight:600;margin:2px 0}
+.card .desc{color:#4b5563;font-size:13px}.card .notes{font-size:12px;color:#b45309;padding:0 12px 8px;margin:0}
+.card.discord{border-left:4px solid #5865f2}.card.slack{border-left:4px solid #e01e5a}
+</style></head><body>
+<header><strong>ogspy</strong> link preview report <small>· {{.Generated}} · v{{.Version}}</small></header>
+{{range .Entries}}<section>
+<h2>{{.URL}}</h2>
+{{if .Error}}<p class="err">✘ {{.Error}}</p>{{else}}<div class="row">
+<div class="cards">{{range .Cards}}
+<div class="card {{.Key}}">
+<div class="label">{{.Platform}}</div>
+{{if .Image}}<img src="{{.Image}}" style="{{.Crop}}" alt="">{{end}}
+<div class="body"><div class="domain">{{.Domain}}</div><div class="title">{{.Title}}</div>{{if .Description}}<div class="desc">{{.Description}}</div>{{end}}</div>
+{{if .Notes}}<ul class="notes">{{range .Notes}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</div>{{end}}
+</div>
+<div class="findings"><strong>Findings</strong>
+{{if .Findings}}<ul>{{range .Findings}}<li class="{{.Severity}}">{{icon .Severity}} {{.Message}} <small>[{{.Rule}}]</small></li>{{end}}</ul>{{else}}<p class="ok">✔ No issues found.</p>{{end}}
+</div></div>{{end}}
+</section>{{end}}
+</body></html>
+`))
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildCard(t *testing.T) {
+	src := cardSources{
+		pageURL: "https://www.example.com/post",
+		og: map[string]string{
+			"title": strings.Repeat("Long headline words ", 10),
+			"image": "/og.png",
+		},
+		twitter:  map[string]string{"title": "Short X title", "image": "https://cdn.example.com/x.png"},
+		fallback: fallbackData{Description: "Meta description"},
+	}
+
+	var fb, x card
+	for _, c := range buildCards(src) {
+		switch c.Key {
+		case "facebook":
+			fb = c
+		case "x":
+			x = c
+		}
+	}
+	if fb.Domain != "EXAMPLE.COM" || string(fb.Image) != "https://www.example.com/og.png" {
+		t.Errorf("facebook card: domain %q image %q", fb.Domain, fb.Image)
+	}
+	if !strings.HasSuffix(fb.Title, "…") || len(fb.Notes) == 0 {
+		t.Errorf("facebook card: expected truncated title, got %q %v", fb.Title, fb.Notes)
+	}
+	if fb.Description != "Meta description" {
+		t.Errorf("facebook card: expected fallback description, got %q", fb.Description)
+	}
+	if x.Title != "Short X title" || string(x.Image) != "https://cdn.example.com/x.png" {
+		t.Errorf("x card must prefer twitter:* tags, got %q %q", x.Title, x.Image)
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	entries := []reportEntry{
+		{URL: "https://example.com", Cards: buildCards(cardSources{pageURL: "https://example.com", og: map[string]string{"title": "<Hi>"}}), Findings: []finding{
+			{Rule: "og-image-missing", Severity: sevError, Message: "og:image is missing"},
+			infof("url-redirect", "og:url redirects"),
+		}},
+		{URL: "https://down.example", Error: "HTTP 503"},
+	}
+	if err := writeHTMLReport(path, entries); err != nil {
+		t.Fatalf("writeHTMLReport error: %v", err)
+	}
+	out, _ := os.ReadFile(path)
+	for _, want := range []string{"Facebook", "Discord", "&lt;Hi&gt;", `<li class="error">✘ og:image is missing <small>[og-image-missing]</small>`, `<li class="info">ℹ og:url redirects`, "HTTP 503"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
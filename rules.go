@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Declarative Rules
+// ------------------------------------------------------------------------------------------------
+
+// rule is one declarative property check loaded from a rules file.
+type rule struct {
+	ID         string   `json:"id,omitempty"`        // stable rule ID; derived from property and check when empty
+	Property   string   `json:"property"`            // full property name, e.g. "og:title" or "article:author"
+	Required   bool     `json:"required,omitempty"`  // the property must be present
+	Essential  bool     `json:"essential,omitempty"` // still checked with --essentials
+	Semantic   bool     `json:"semantic,omitempty"`  // only checked with --semantic
+	Types      []string `json:"types,omitempty"`     // og:type values the rule applies to ("video.*" matches a namespace)
+	Pattern    string   `json:"pattern,omitempty"`   // regular expression the value must match
+	MinLength  int      `json:"min_length,omitempty"`
+	MaxLength  int      `json:"max_length,omitempty"`
+	LengthUnit string   `json:"length_unit,omitempty"` // "characters" (default) or "graphemes"
+	Severity   string   `json:"severity,omitempty"`    // "error" (default), "warning" or "info"
+	Message    string   `json:"message,omitempty"`     // replaces the generated message
+
+	pattern *regexp.Regexp
+}
+
+// ruleSet is the contents of a rules file.
+type ruleSet struct {
+	Rules  []rule       `json:"rules"`
+	Ignore []ignoreRule `json:"ignore,omitempty"` // suppressed rule IDs, optionally per URL
+}
+
+// defaultRulesJSON is the built-in rule set: the five essential tags, the
+// recommended superset checked by plain `validate` (og:video and og:audio as
+// info, article:* only on articles), the per-type properties checked by
+// --semantic and the value formats of the music, video, book and profile
+// schemas. It doubles as the reference for writing a custom --rules file.
+const defaultRulesJSON = `{
+  "rules": [
+    {"property": "og:title", "required": true, "essential": true},
+    {"property": "og:type", "required": true, "essential": true},
+    {"property": "og:image", "required": true, "essential": true},
+    {"property": "og:url", "required": true, "essential": true},
+    {"property": "og:description", "required": true, "essential": true},
+    {"property": "og:site_name", "required": true},
+    {"property": "og:locale", "required": true},
+    {"property": "og:video", "required": true, "severity": "info"},
+    {"property": "og:audio", "required": true, "severity": "info"},
+    {"property": "article:author", "required": true, "types": ["article"]},
+    {"property": "article:publisher", "required": true, "types": ["article"]},
+    {"property": "article:section", "required": true, "types": ["article"]},
+    {"property": "article:tag", "required": true, "types": ["article"]},
+    {"id": "article-author-required-for-article", "property": "article:author", "required": true, "essential": true, "semantic": true, "types": ["article"], "severity": "warning"},
+    {"id": "article-section-required-for-article", "property": "article:section", "required": true, "essential": true, "semantic": true, "types": ["article"], "severity": "warning"},
+    {"property": "music:duration", "required": true, "essential": true, "semantic": true, "types": ["music.song"], "severity": "warning"},
+    {"property": "music:musician", "required": true, "essential": true, "semantic": true, "types": ["music.song", "music.album"], "severity": "warning"},
+    {"property": "music:song", "required": true, "essential": true, "semantic": true, "types": ["music.album", "music.playlist"], "severity": "warning"},
+    {"property": "music:creator", "required": true, "essential": true, "semantic": true, "types": ["music.playlist", "music.radio_station"], "severity": "warning"},
+    {"property": "video:release_date", "required": true, "essential": true, "semantic": true, "types": ["video.movie"], "severity": "warning"},
+    {"property": "video:director", "required": true, "essential": true, "semantic": true, "types": ["video.movie"], "severity": "warning"},
+    {"property": "video:series", "required": true, "essential": true, "semantic": true, "types": ["video.episode"], "severity": "warning"},
+    {"property": "book:author", "required": true, "essential": true, "semantic": true, "types": ["book"], "severity": "warning"},
+    {"property": "book:isbn", "required": true, "essential": true, "semantic": true, "types": ["book"], "severity": "warning"},
+    {"property": "profile:first_name", "required": true, "essential": true, "semantic": true, "types": ["profile"], "severity": "warning"},
+    {"property": "profile:last_name", "required": true, "essential": true, "semantic": true, "types": ["profile"], "severity": "warning"},
+    {"property": "music:duration", "types": ["music.song"], "pattern": "^[1-9][0-9]*$", "severity": "warning"},
+    {"property": "music:album", "types": ["music.song"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "music:album:disc", "types": ["music.song"], "pattern": "^[1-9][0-9]*$", "severity": "warning"},
+    {"property": "music:album:track", "types": ["music.song"], "pattern": "^[1-9][0-9]*$", "severity": "warning"},
+    {"property": "music:musician", "types": ["music.song", "music.album"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "music:song", "types": ["music.album", "music.playlist"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "music:song:disc", "types": ["music.album", "music.playlist"], "pattern": "^[1-9][0-9]*$", "severity": "warning"},
+    {"property": "music:song:track", "types": ["music.album", "music.playlist"], "pattern": "^[1-9][0-9]*$", "severity": "warning"},
+    {"property": "music:release_date", "types": ["music.album"], "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}", "severity": "warning"},
+    {"property": "music:creator", "types": ["music.playlist", "music.radio_station"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "video:actor", "types": ["video.*"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "video:director", "types": ["video.*"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "video:writer", "types": ["video.*"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "video:duration", "types": ["video.*"], "pattern": "^[1-9][0-9]*$", "severity": "warning"},
+    {"property": "video:release_date", "types": ["video.*"], "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}", "severity": "warning"},
+    {"property": "video:series", "types": ["video.episode"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "book:author", "types": ["book"], "pattern": "^https?://", "severity": "warning"},
+    {"property": "book:isbn", "types": ["book"], "pattern": "^(?:97[89]-?)?[0-9](?:-?[0-9]){8}-?[0-9Xx]$", "severity": "warning"},
+    {"property": "book:release_date", "types": ["book"], "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}", "severity": "warning"},
+    {"property": "profile:gender", "types": ["profile"], "pattern": "^(?:male|female)$", "severity": "warning"},
+    {"property": "profile:username", "types": ["profile"], "pattern": "^[^\\s]+$", "severity": "warning"}
+  ]
+}`
+
+// builtinRules is the parsed default rule set.
+var builtinRules = func() *ruleSet {
+	rs, err := parseRules([]byte(defaultRulesJSON))
+	if err != nil {
+		panic(err)
+	}
+	return rs
+}()
+
+// loadRules reads a rules file, or returns the built-in set when path is empty.
+func loadRules(path string) (*ruleSet, error) {
+	if path == "" {
+		return builtinRules, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := parseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// parseRules decodes and checks a rules document. Unknown fields are rejected
+// so that typos do not silently disable a rule.
+func parseRules(data []byte) (*ruleSet, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var rs ruleSet
+	if err := dec.Decode(&rs); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Property = strings.TrimSpace(r.Property); r.Property == "" {
+			return nil, fmt.Errorf("rule %d: property is required", i+1)
+		}
+		switch r.Severity {
+		case "":
+			r.Severity = sevError
+		case sevError, sevWarning, sevInfo:
+		default:
+			return nil, fmt.Errorf("rule %d (%s): unknown severity %q (want error, warning or info)", i+1, r.Property, r.Severity)
+		}
+		if r.MinLength < 0 || r.MaxLength < 0 || (r.MaxLength > 0 && r.MinLength > r.MaxLength) {
+			return nil, fmt.Errorf("rule %d (%s): invalid length bounds %d–%d", i+1, r.Property, r.MinLength, r.MaxLength)
+		}
+		switch r.LengthUnit {
+		case "":
+			r.LengthUnit = "characters"
+		case "characters", "graphemes":
+		default:
+			return nil, fmt.Errorf("rule %d (%s): unknown length_unit %q (want characters or graphemes)", i+1, r.Property, r.LengthUnit)
+		}
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d (%s): %w", i+1, r.Property, err)
+			}
+			r.pattern = re
+		}
+	}
+	// Two rules sharing an ID could not be told apart by --fail-on, SARIF or
+	// an ignore entry.
+	ids := make(map[string]int)
+	for i, r := range rs.Rules {
+		for _, id := range r.ids() {
+			if j, dup := ids[id]; dup {
+				return nil, fmt.Errorf("rule %d (%s): ID %s is already used by rule %d; set a distinct \"id\"", i+1, r.Property, id, j)
+			}
+			ids[id] = i + 1
+		}
+	}
+	for i, ig := range rs.Ignore {
+		if strings.TrimSpace(ig.Rule) == "" {
+			return nil, fmt.Errorf("ignore %d: rule is required", i+1)
+		}
+	}
+	return &rs, nil
+}
+
+// ruleID is the ID of the findings r reports for check ("missing",
+// "pattern" or "length"): the explicit ID, or one derived from the property.
+func (r rule) ruleID(check string) string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return strings.NewReplacer(":", "-", ".", "-").Replace(r.Property) + "-" + check
+}
+
+// ids lists the distinct IDs r can report.
+func (r rule) ids() []string {
+	var out []string
+	for _, c := range []struct {
+		check string
+		on    bool
+	}{
+		{"missing", r.Required},
+		{"pattern", r.Pattern != ""},
+		{"length", r.MinLength > 0 || r.MaxLength > 0},
+	} {
+		if id := r.ruleID(c.check); c.on && !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// essentials lists the properties of the essential presence rules.
+func (rs *ruleSet) essentials() []string {
+	var out []string
+	for _, r := range rs.Rules {
+		if r.Required && r.Essential && !r.Semantic && len(r.Types) == 0 && !slices.Contains(out, r.Property) {
+			out = append(out, r.Property)
+		}
+	}
+	return out
+}
+
+// check evaluates the rules against the page's meta tags, keyed by full
+// property or name (see parseMeta with an empty prefix).
+func (rs *ruleSet) check(meta map[string]string, essentialsOnly, semantic bool) []finding {
+	var out []finding
+	seen := make(map[finding]bool)
+	add := func(r rule, check string, missing bool, msg string) {
+		if r.Message != "" {
+			msg = r.Message
+		}
+		v := finding{Rule: r.ruleID(check), Severity: r.Severity, Message: msg, Property: r.Property, Missing: missing,
+			recommended: !r.Essential && len(r.Types) == 0}
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+
+	ogType := strings.TrimSpace(meta["og:type"])
+	for _, r := range rs.Rules {
+		if (essentialsOnly && !r.Essential) || (r.Semantic && !semantic) || !matchesType(r.Types, ogType) {
+			continue
+		}
+		value := strings.TrimSpace(metaValue(meta, r.Property))
+		if value == "" {
+			if r.Required {
+				add(r, "missing", true, r.Property+" is missing")
+			}
+			continue
+		}
+		if r.pattern != nil && !r.pattern.MatchString(value) {
+			add(r, "pattern", false, fmt.Sprintf("%s %q does not match %s", r.Property, value, r.Pattern))
+		}
+		n := utf8.RuneCountInString(value)
+		if r.LengthUnit == "graphemes" {
+			n = len(graphemes(value))
+		}
+		if r.MinLength > 0 && n < r.MinLength {
+			add(r, "length", false, fmt.Sprintf("%s is too short (%d %s, minimum %d)", r.Property, n, r.LengthUnit, r.MinLength))
+		}
+		if r.MaxLength > 0 && n > r.MaxLength {
+			add(r, "length", false, fmt.Sprintf("%s is too long (%d %s, maximum %d)", r.Property, n, r.LengthUnit, r.MaxLength))
+		}
+	}
+	return out
+}
+
+// metaValue looks up property in meta. Properties outside the og: and
+// twitter: namespaces (article:*, book:*, …) are also accepted with an "og:"
+// prefix, which ogspy historically required.
+func metaValue(meta map[string]string, property string) string {
+	if v, ok := meta[property]; ok || strings.HasPrefix(property, "og:") || strings.HasPrefix(property, "twitter:") {
+		return v
+	}
+	return meta["og:"+property]
+}
+
+// matchesType reports whether ogType satisfies a rule's type condition.
+func matchesType(types []string, ogType string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == ogType || (strings.HasSuffix(t, ".*") && strings.HasPrefix(ogType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
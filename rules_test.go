@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func missingProperties(vs []finding, severity string) []string {
+	var out []string
+	for _, v := range vs {
+		if v.Missing && v.Severity == severity {
+			out = append(out, v.Property)
+		}
+	}
+	return out
+}
+
+func TestBuiltinRules(t *testing.T) {
+	meta := map[string]string{"og:title": "Hello", "og:type": "article", "article:author": "https://example.com/me"}
+
+	got := missingProperties(builtinRules.check(meta, true, false), "error")
+	if want := []string{"og:image", "og:url", "og:description"}; !slices.Equal(got, want) {
+		t.Errorf("essentials missing = %v, want %v", got, want)
+	}
+	got = missingProperties(builtinRules.check(meta, false, false), "error")
+	if len(got) != 8 || slices.Contains(got, "article:author") || slices.Contains(got, "og:video") {
+		t.Errorf("recommended missing = %v, want 8 tags without article:author or og:video", got)
+	}
+
+	// A complete website has no errors: article:* does not apply and the
+	// missing og:video and og:audio are info.
+	website := map[string]string{"og:title": "Hello", "og:type": "website", "og:image": "https://example.com/a.png", "og:url": "https://example.com/",
+		"og:description": "Hi", "og:site_name": "Example", "og:locale": "en_US"}
+	if got := missingProperties(builtinRules.check(website, false, true), "error"); len(got) != 0 {
+		t.Errorf("website missing errors = %v, want none", got)
+	}
+	if got := missingProperties(builtinRules.check(website, false, true), "info"); !slices.Equal(got, []string{"og:video", "og:audio"}) {
+		t.Errorf("website missing info = %v, want [og:video og:audio]", got)
+	}
+
+	// The article checks are --semantic warnings.
+	if w := missingProperties(builtinRules.check(meta, true, false), "warning"); len(w) != 0 {
+		t.Errorf("non-semantic warnings = %v, want none", w)
+	}
+	if w := missingProperties(builtinRules.check(meta, true, true), "warning"); !slices.Equal(w, []string{"article:section"}) {
+		t.Errorf("semantic warnings = %v, want [article:section]", w)
+	}
+
+	// article:* is still accepted with the legacy og: prefix.
+	meta["og:article:section"] = "News"
+	if w := missingProperties(builtinRules.check(meta, true, true), "warning"); len(w) != 0 {
+		t.Errorf("og:article:section not honoured: %v", w)
+	}
+
+	if e := builtinRules.essentials(); len(e) != 5 {
+		t.Errorf("essentials() = %v, want 5 properties", e)
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	rs, err := parseRules([]byte(`{"rules": [
+		{"property": "og:title", "required": true, "min_length": 10, "max_length": 20},
+		{"property": "og:url", "pattern": "^https://", "severity": "warning"},
+		{"property": "video:duration", "required": true, "types": ["video.*"], "severity": "info"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vs := rs.check(map[string]string{"og:title": "Short", "og:url": "http://x", "og:type": "website"}, false, false)
+	if len(vs) != 2 || vs[0].Severity != "error" || !strings.Contains(vs[0].Message, "too short") || vs[1].Severity != "warning" {
+		t.Errorf("website violations = %+v", vs)
+	}
+	vs = rs.check(map[string]string{"og:title": "Exactly fifteen", "og:url": "https://x", "og:type": "video.movie"}, false, false)
+	if len(vs) != 1 || vs[0].Property != "video:duration" || !vs[0].Missing || vs[0].Severity != "info" {
+		t.Errorf("video violations = %+v", vs)
+	}
+
+	for _, bad := range []string{
+		`{"rules": [{"property": ""}]}`,
+		`{"rules": [{"property": "og:title", "severity": "fatal"}]}`,
+		`{"rules": [{"property": "og:title", "pattern": "("}]}`,
+		`{"rules": [{"property": "og:title", "min_length": 9, "max_length": 3}]}`,
+		`{"rules": [{"property": "og:title", "requird": true}]}`,
+		`{"rules": [{"property": "og:title", "required": true}, {"property": "og:title", "required": true, "severity": "warning"}]}`,
+		`{"rules": [{"id": "title", "property": "og:title", "required": true}, {"id": "title", "property": "og:description", "pattern": "."}]}`,
+	} {
+		if _, err := parseRules([]byte(bad)); err == nil {
+			t.Errorf("parseRules(%s) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	if rs, err := loadRules(""); err != nil || rs != builtinRules {
+		t.Fatalf("loadRules(\"\") = %v, %v; want built-in rules", rs, err)
+	}
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`{"rules": [{"property": "og:title", "required": true}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := loadRules(path)
+	if err != nil || len(rs.Rules) != 1 {
+		t.Fatalf("loadRules(file) = %v, %v", rs, err)
+	}
+}
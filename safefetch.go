@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ------------------------------------------------------------------------------------------------
+// SSRF-Safe Fetching
+// ------------------------------------------------------------------------------------------------
+
+// fetchPolicy restricts where ogspy may connect. Host and port lists apply
+// whenever they are set; IP range blocking is enabled by --safe-fetch.
+type fetchPolicy struct {
+	safe       bool
+	allowHosts []string // exact names, "*.suffix" wildcards or CIDR prefixes
+	denyHosts  []string
+	allowPorts []int
+	denyPorts  []int
+}
+
+// blockedRanges are never fetched in safe mode: loopback, private, link-local
+// (which includes 169.254.169.254), CGNAT (Alibaba's 100.100.100.200), ULA
+// (AWS's fd00:ec2::254) and other special-purpose space.
+var blockedRanges = []struct {
+	prefix netip.Prefix
+	reason string
+}{
+	{netip.MustParsePrefix("0.0.0.0/8"), "unspecified address"},
+	{netip.MustParsePrefix("10.0.0.0/8"), "private address"},
+	{netip.MustParsePrefix("100.64.0.0/10"), "carrier-grade NAT / cloud metadata address"},
+	{netip.MustParsePrefix("127.0.0.0/8"), "loopback address"},
+	{netip.MustParsePrefix("169.254.0.0/16"), "link-local address (cloud metadata endpoint)"},
+	{netip.MustParsePrefix("172.16.0.0/12"), "private address"},
+	{netip.MustParsePrefix("192.0.0.0/24"), "IETF protocol assignment"},
+	{netip.MustParsePrefix("192.168.0.0/16"), "private address"},
+	{netip.MustParsePrefix("198.18.0.0/15"), "benchmarking address"},
+	{netip.MustParsePrefix("224.0.0.0/4"), "multicast address"},
+	{netip.MustParsePrefix("240.0.0.0/4"), "reserved address"},
+	{netip.MustParsePrefix("::/128"), "unspecified address"},
+	{netip.MustParsePrefix("::1/128"), "loopback address"},
+	{netip.MustParsePrefix("64:ff9b::/96"), "NAT64 address"},
+	{netip.MustParsePrefix("2002::/16"), "6to4 address (may embed a private IPv4 address)"},
+	{netip.MustParsePrefix("fc00::/7"), "unique local address (cloud metadata endpoint)"},
+	{netip.MustParsePrefix("fe80::/10"), "link-local address"},
+	{netip.MustParsePrefix("ff00::/8"), "multicast address"},
+}
+
+// blockedError explains why a request was refused.
+type blockedError struct {
+	target string
+	reason string
+}
+
+func (e *blockedError) Error() string {
+	return fmt.Sprintf("safe-fetch blocked %s: %s", e.target, e.reason)
+}
+
+// active reports whether the policy restricts anything at all.
+func (p *fetchPolicy) active() bool {
+	return p.safe || len(p.allowHosts)+len(p.denyHosts)+len(p.allowPorts)+len(p.denyPorts) > 0
+}
+
+// matchHost reports whether host (or its resolved ip, when valid) matches any
+// entry of list.
+func matchHost(list []string, host string, ip netip.Addr) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, entry := range list {
+		entry = strings.ToLower(entry)
+		if pfx, err := netip.ParsePrefix(entry); err == nil {
+			if ip.IsValid() && pfx.Contains(ip.Unmap()) {
+				return true
+			}
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			if ip.IsValid() && addr == ip.Unmap() {
+				return true
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(entry, "*."):
+			if strings.HasSuffix(host, entry[1:]) {
+				return true
+			}
+		case host == entry:
+			return true
+		}
+	}
+	return false
+}
+
+// checkName applies the host and port lists to the requested name.
+func (p *fetchPolicy) checkName(host string, port int) error {
+	target := net.JoinHostPort(host, strconv.Itoa(port))
+	if slices.Contains(p.denyPorts, port) {
+		return &blockedError{target, fmt.Sprintf("port %d is denylisted", port)}
+	}
+	if len(p.allowPorts) > 0 && !slices.Contains(p.allowPorts, port) {
+		return &blockedError{target, fmt.Sprintf("port %d is not in the allowlist", port)}
+	}
+	if matchHost(p.denyHosts, host, netip.Addr{}) {
+		return &blockedError{target, "host is denylisted"}
+	}
+	return nil
+}
+
+// checkIP applies the host lists and, in safe mode, the blocked ranges to an
+// address the name resolved to.
+func (p *fetchPolicy) checkIP(host string, port int, ip netip.Addr) error {
+	target := net.JoinHostPort(host, strconv.Itoa(port))
+	if ip.String() != host {
+		target += " (" + ip.String() + ")"
+	}
+	if matchHost(p.denyHosts, host, ip) {
+		return &blockedError{target, "address is denylisted"}
+	}
+	allowed := matchHost(p.allowHosts, host, ip)
+	if len(p.allowHosts) > 0 && !allowed {
+		return &blockedError{target, "host is not in the allowlist"}
+	}
+	if !p.safe || p.allowsAddr(ip) {
+		return nil
+	}
+	for _, r := range blockedRanges {
+		if r.prefix.Contains(ip.Unmap()) {
+			return &blockedError{target, r.reason}
+		}
+	}
+	return nil
+}
+
+// allowsAddr reports whether ip itself (not just its name) is allowlisted, the
+// only way to reach a blocked range in safe mode.
+func (p *fetchPolicy) allowsAddr(ip netip.Addr) bool {
+	return matchHost(p.allowHosts, "", ip)
+}
+
+// checkRedirect re-applies the name checks to every redirect hop, so a
+// denylisted host is reported even when a pooled connection would be reused.
+func (p *fetchPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	port, _ := strconv.Atoi(req.URL.Port())
+	if port == 0 {
+		port = 80
+		if req.URL.Scheme == "https" {
+			port = 443
+		}
+	}
+	return p.checkName(req.URL.Hostname(), port)
+}
+
+// dialSafe resolves address itself, vets every returned IP and connects to a
+// vetted IP directly, so DNS rebinding cannot swap the target after the check.
+func (d *dialer) dialSafe(ctx context.Context, network, address, target string) (net.Conn, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, _ := strconv.Atoi(portStr)
+	if err := d.policy.checkName(host, port); err != nil {
+		return nil, err
+	}
+
+	dialHost, _, _ := net.SplitHostPort(target)
+	var ips []netip.Addr
+	if ip, err := netip.ParseAddr(dialHost); err == nil {
+		ips = []netip.Addr{ip}
+	} else {
+		resolver := d.net.Resolver
+		if resolver == nil {
+			resolver = net.DefaultResolver
+		}
+		trace := httptrace.ContextClientTrace(ctx)
+		if trace != nil && trace.DNSStart != nil {
+			trace.DNSStart(httptrace.DNSStartInfo{Host: dialHost})
+		}
+		ips, err = resolver.LookupNetIP(ctx, "ip", dialHost)
+		if trace != nil && trace.DNSDone != nil {
+			trace.DNSDone(httptrace.DNSDoneInfo{Err: err})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, ip := range ips {
+		if err := d.policy.checkIP(host, port, ip); err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := d.net.DialContext(ctx, network, net.JoinHostPort(ip.Unmap().String(), portStr))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
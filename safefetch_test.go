@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestFetchPolicyCheckIP(t *testing.T) {
+	p := fetchPolicy{safe: true}
+	blocked := []string{"127.0.0.1", "10.1.2.3", "169.254.169.254", "100.100.100.200", "::1", "fd00:ec2::254", "::ffff:192.168.1.1", "2002:c0a8:101::1"}
+	for _, ip := range blocked {
+		if err := p.checkIP("example.com", 80, netip.MustParseAddr(ip)); err == nil {
+			t.Errorf("checkIP(%s): expected block", ip)
+		}
+	}
+	if err := p.checkIP("example.com", 443, netip.MustParseAddr("93.184.216.34")); err != nil {
+		t.Errorf("checkIP(public): unexpected error %v", err)
+	}
+
+	p.allowHosts = []string{"10.0.0.0/24"}
+	if err := p.checkIP("intranet", 80, netip.MustParseAddr("10.0.0.5")); err != nil {
+		t.Errorf("checkIP(allowlisted CIDR): unexpected error %v", err)
+	}
+	if err := p.checkIP("example.com", 80, netip.MustParseAddr("93.184.216.34")); err == nil {
+		t.Error("checkIP: host outside the allowlist must be blocked")
+	}
+
+	p = fetchPolicy{denyHosts: []string{"*.internal.example"}, denyPorts: []int{22}}
+	if err := p.checkName("db.internal.example", 443); err == nil {
+		t.Error("checkName: wildcard denylist not applied")
+	}
+	if err := p.checkName("example.com", 22); err == nil {
+		t.Error("checkName: port denylist not applied")
+	}
+}
+
+func TestSafeFetchBlocksLoopbackAndRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hop" {
+			http.Redirect(w, r, "http://metadata.internal.example/latest", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("<html><head></head><body>ok</body></html>"))
+	}))
+	defer srv.Close()
+
+	prev := httpClient
+	defer func() { httpClient = prev }()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var err error
+	if httpClient, err = newHTTPClient(transportOptions{policy: fetchPolicy{safe: true}}); err != nil {
+		t.Fatal(err)
+	}
+	var blocked *blockedError
+	if _, err := fetchHTML(ctx, srv.URL); !errors.As(err, &blocked) {
+		t.Fatalf("fetchHTML(loopback) error = %v, want blockedError", err)
+	}
+
+	httpClient, _ = newHTTPClient(transportOptions{policy: fetchPolicy{
+		safe:       true,
+		allowHosts: []string{"127.0.0.1"},
+		denyHosts:  []string{"*.internal.example"},
+	}})
+	if _, err := fetchHTML(ctx, srv.URL); err != nil {
+		t.Fatalf("fetchHTML(allowlisted) error: %v", err)
+	}
+	if _, err := fetchHTML(ctx, srv.URL+"/hop"); !errors.As(err, &blocked) {
+		t.Fatalf("fetchHTML(redirect) error = %v, want blockedError", err)
+	}
+}
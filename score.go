@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Preview Quality Score
+// ------------------------------------------------------------------------------------------------
+
+// scoreCategories are the score components and their weights, which add up
+// to 100.
+var scoreCategories = []struct {
+	Name   string
+	Weight int
+}{
+	{"presence", 35},
+	{"image", 25},
+	{"lengths", 15},
+	{"consistency", 15},
+	{"accessibility", 10},
+}
+
+// scorePenalty is what one finding of a severity costs its category.
+var scorePenalty = map[string]int{sevError: 40, sevWarning: 15, sevInfo: 3}
+
+// score is a 0–100 rating of a page's previews with its breakdown.
+type score struct {
+	Total     int         `json:"total"`
+	Breakdown []scorePart `json:"breakdown"`
+	Partial   bool        `json:"partial,omitempty"` // from inspect, without validate's accessibility and --semantic checks
+}
+
+// scorePart is one weighted category of a score.
+type scorePart struct {
+	Category string `json:"category"`
+	Weight   int    `json:"weight"`
+	Score    int    `json:"score"` // 0–100 within the category
+	Findings int    `json:"findings"`
+}
+
+// scoreCategory files a finding under a score category by its rule ID;
+// findings that say nothing about the preview itself (timing budgets, crop
+// output, snapshot drift) return "".
+func scoreCategory(f finding) string {
+	id := f.Rule
+	switch {
+	case strings.HasPrefix(id, "alt-") || strings.HasPrefix(id, "a11y-"):
+		return "accessibility"
+	case id == "image-crop" || strings.HasPrefix(id, "snapshot-"):
+		return ""
+	case f.Missing || strings.HasSuffix(id, "-missing") || id == "client-side-tags":
+		return "presence"
+	case strings.HasPrefix(id, "image-") || strings.HasPrefix(id, "media-"):
+		return "image"
+	case strings.HasSuffix(id, "-length") || strings.HasPrefix(id, "title-truncated-") || strings.HasPrefix(id, "description-truncated-"):
+		return "lengths"
+	case hasAnyPrefix(id, []string{"canonical-", "og-url-", "url-", "locale-", "hreflang-", "article-", "og-type-", "link-"}) || strings.HasSuffix(id, "-pattern"):
+		return "consistency"
+	}
+	return ""
+}
+
+// findingPenalty is what f costs its category: the penalty of its severity,
+// but at most that of an info finding for a recommended tag (neither
+// essential nor required by the page's og:type), and nothing for an optional
+// one reported as info.
+func findingPenalty(f finding) int {
+	p := scorePenalty[f.Severity]
+	if f.recommended {
+		if f.Severity == sevInfo {
+			return 0
+		}
+		p = min(p, scorePenalty[sevInfo])
+	}
+	return p
+}
+
+// computeScore rates fs: every category starts at 100 and loses a penalty
+// per finding (see findingPenalty); the total is the weighted average.
+func computeScore(fs []finding) score {
+	lost := map[string]int{}
+	count := map[string]int{}
+	for _, f := range fs {
+		if c := scoreCategory(f); c != "" {
+			lost[c] += findingPenalty(f)
+			count[c]++
+		}
+	}
+	var s score
+	var total float64
+	for _, c := range scoreCategories {
+		part := scorePart{Category: c.Name, Weight: c.Weight, Score: max(100-lost[c.Name], 0), Findings: count[c.Name]}
+		total += float64(part.Score*c.Weight) / 100
+		s.Breakdown = append(s.Breakdown, part)
+	}
+	s.Total = int(math.Round(total))
+	return s
+}
+
+// scoreColor picks green, yellow or red for a 0–100 value.
+func scoreColor(v int) *color.Color {
+	switch {
+	case v >= 90:
+		return color.New(color.FgGreen, color.Bold)
+	case v >= 60:
+		return color.New(color.FgYellow, color.Bold)
+	}
+	return color.New(color.FgRed, color.Bold)
+}
+
+// printScore renders the total with one bar per category.
+func printScore(s score) {
+	fmt.Println()
+	fmt.Print("Score: ")
+	scoreColor(s.Total).Printf("%d/100", s.Total)
+	dim := color.New(color.FgHiBlack)
+	if s.Partial {
+		dim.Print(" (partial: run validate for accessibility and --semantic checks)")
+	}
+	fmt.Println()
+	for _, p := range s.Breakdown {
+		filled := p.Score / 10
+		fmt.Printf("  %-14s ", p.Category)
+		scoreColor(p.Score).Print(strings.Repeat("█", filled))
+		dim.Print(strings.Repeat("░", 10-filled))
+		fmt.Printf(" %3d", p.Score)
+		dim.Printf("  ×%d%%", p.Weight)
+		if p.Findings > 0 {
+			dim.Printf(", %d finding(s)", p.Findings)
+		}
+		fmt.Println()
+	}
+}
+
+// siteAverage is the mean score of the URLs on one host.
+type siteAverage struct {
+	Site    string
+	URLs    int
+	Average float64
+}
+
+// siteAverages groups scores by host, sorted by host.
+func siteAverages(scores map[string]int) []siteAverage {
+	sums := map[string]*siteAverage{}
+	for raw, v := range scores {
+		host := raw
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			host = strings.ToLower(u.Host)
+		}
+		a := sums[host]
+		if a == nil {
+			a = &siteAverage{Site: host}
+			sums[host] = a
+		}
+		a.URLs++
+		a.Average += float64(v)
+	}
+	out := make([]siteAverage, 0, len(sums))
+	for _, a := range sums {
+		a.Average /= float64(a.URLs)
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Site < out[j].Site })
+	return out
+}
+
+// printSiteAverages prints the average score of every site in a batch run.
+func printSiteAverages(scores map[string]int) {
+	color.New(color.Bold).Println("\nAverage score by site:")
+	for _, a := range siteAverages(scores) {
+		fmt.Printf("  %-30s ", a.Site)
+		scoreColor(int(math.Round(a.Average))).Printf("%5.1f", a.Average)
+		color.New(color.FgHiBlack).Printf("  (%d URL(s))\n", a.URLs)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComputeScore(t *testing.T) {
+	if s := computeScore(nil); s.Total != 100 || len(s.Breakdown) != len(scoreCategories) {
+		t.Errorf("clean page score = %+v", s)
+	}
+	weights := 0
+	for _, c := range scoreCategories {
+		weights += c.Weight
+	}
+	if weights != 100 {
+		t.Errorf("category weights add up to %d", weights)
+	}
+
+	fs := []finding{
+		{Rule: "og-image-missing", Severity: sevError, Property: "og:image", Missing: true}, // presence −40
+		warnf("image-ratio", "ratio"),     // image −15
+		warnf("title-truncated-x", "cut"), // lengths −15
+		infof("url-redirect", "redirect"), // consistency −3
+		warnf("alt-missing", "no alt"),    // accessibility −15
+		warnf("budget-ttfb", "slow"),      // not scored
+	}
+	s := computeScore(fs)
+	want := map[string]int{"presence": 60, "image": 85, "lengths": 85, "consistency": 97, "accessibility": 85}
+	var total float64
+	for _, p := range s.Breakdown {
+		if p.Score != want[p.Category] || p.Findings != 1 {
+			t.Errorf("%s = %d (%d findings), want %d", p.Category, p.Score, p.Findings, want[p.Category])
+		}
+		total += float64(p.Score*p.Weight) / 100
+	}
+	if s.Total != int(math.Round(total)) || s.Total != 78 {
+		t.Errorf("total = %d, want 78", s.Total)
+	}
+
+	// Categories bottom out at zero.
+	var many []finding
+	for range 5 {
+		many = append(many, finding{Rule: "og-title-missing", Severity: sevError, Missing: true})
+	}
+	if p := computeScore(many).Breakdown[0]; p.Score != 0 {
+		t.Errorf("presence = %d, want 0", p.Score)
+	}
+}
+
+func TestScorePresence(t *testing.T) {
+	// A complete website is not charged for tags that only apply to other
+	// types (article:*) or are optional (og:video, og:audio).
+	website := map[string]string{"og:title": "Hello", "og:type": "website", "og:image": "https://example.com/a.png", "og:url": "https://example.com/",
+		"og:description": "Hi", "og:site_name": "Example", "og:locale": "en_US"}
+	if p := computeScore(builtinRules.check(website, false, true)).Breakdown[0]; p.Category != "presence" || p.Score != 100 {
+		t.Errorf("complete website presence = %+v, want 100", p)
+	}
+
+	// A missing recommended tag costs no more than an info finding, a
+	// missing essential the full error penalty.
+	delete(website, "og:site_name")
+	if p := computeScore(builtinRules.check(website, false, true)).Breakdown[0]; p.Score != 100-scorePenalty[sevInfo] {
+		t.Errorf("without og:site_name presence = %d, want %d", p.Score, 100-scorePenalty[sevInfo])
+	}
+	delete(website, "og:image")
+	if p := computeScore(builtinRules.check(website, false, true)).Breakdown[0]; p.Score != 100-scorePenalty[sevInfo]-scorePenalty[sevError] {
+		t.Errorf("without og:image presence = %d", p.Score)
+	}
+}
+
+func TestSiteAverages(t *testing.T) {
+	got := siteAverages(map[string]int{
+		"https://a.example/1": 80,
+		"https://A.example/2": 90,
+		"https://b.example/":  40,
+	})
+	if len(got) != 2 || got[0].Site != "a.example" || got[0].URLs != 2 || got[0].Average != 85 || got[1].Average != 40 {
+		t.Errorf("siteAverages = %+v", got)
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Golden Snapshots
+// ------------------------------------------------------------------------------------------------
+
+// snapshot is the stored OG map of one URL. encoding/json sorts map keys, so
+// the file is stable across runs and diffs cleanly in review.
+type snapshot struct {
+	URL string            `json:"url"`
+	OG  map[string]string `json:"og"`
+}
+
+// snapshotPath names the snapshot file of u in dir: a readable slug of the
+// URL plus a short hash, so that URLs differing only in their query or past
+// the slug length do not collide.
+func snapshotPath(dir, u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(dir, cropSlug(u)+"-"+hex.EncodeToString(sum[:4])+".json")
+}
+
+// writeSnapshot stores og as the snapshot of u and returns the file path. The
+// file is written under a temporary name and renamed into place, so readers
+// and concurrent writers of the same URL never see a partial file.
+func writeSnapshot(dir, u string, og map[string]string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if og == nil {
+		og = map[string]string{}
+	}
+	data, err := json.MarshalIndent(snapshot{URL: u, OG: og}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := snapshotPath(dir, u)
+	f, err := os.CreateTemp(dir, ".snapshot-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name()) // no-op once renamed
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return path, os.Rename(f.Name(), path)
+}
+
+// readSnapshot loads the snapshot of u; a missing file yields an error
+// wrapping os.ErrNotExist.
+func readSnapshot(dir, u string) (*snapshot, error) {
+	data, err := os.ReadFile(snapshotPath(dir, u))
+	if err != nil {
+		return nil, err
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", snapshotPath(dir, u), err)
+	}
+	return &s, nil
+}
+
+// snapshotCheck is the outcome of comparing a page with its snapshot.
+type snapshotCheck struct {
+	Path     string
+	Diff     map[string][2]string // (snapshot, live) per differing og: key
+	Updated  bool                 // --update rewrote the snapshot
+	Findings []finding
+}
+
+// checkSnapshot compares the live OG map of u with its snapshot in dir. With
+// update set, a missing or differing snapshot is rewritten instead of
+// reported.
+func checkSnapshot(dir, u string, og map[string]string, update bool) snapshotCheck {
+	c := snapshotCheck{Path: snapshotPath(dir, u)}
+	old, err := readSnapshot(dir, u)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !update:
+		c.Findings = []finding{{Rule: "snapshot-missing", Severity: sevError, Message: fmt.Sprintf("no snapshot of %s in %s; run with --update to record one", u, dir)}}
+		return c
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		c.Findings = []finding{{Rule: "snapshot-unreadable", Severity: sevError, Message: err.Error()}}
+		return c
+	case err == nil:
+		c.Diff = diffMaps(old.OG, og)
+		if len(c.Diff) == 0 {
+			return c
+		}
+	}
+	if update {
+		if _, err := writeSnapshot(dir, u, og); err != nil {
+			c.Findings = []finding{{Rule: "snapshot-write-failed", Severity: sevError, Message: err.Error()}}
+			return c
+		}
+		c.Updated = true
+		return c
+	}
+
+	keys := make([]string, 0, len(c.Diff))
+	for k := range c.Diff {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := c.Diff[k]
+		var msg string
+		switch {
+		case v[0] == "":
+			msg = fmt.Sprintf("og:%s was added: %q", k, v[1])
+		case v[1] == "":
+			msg = fmt.Sprintf("og:%s was removed (was %q)", k, v[0])
+		default:
+			msg = fmt.Sprintf("og:%s changed from %q to %q", k, v[0], v[1])
+		}
+		c.Findings = append(c.Findings, finding{Rule: "snapshot-changed", Severity: sevError, Message: msg, Property: "og:" + k})
+	}
+	return c
+}
+
+// printSnapshot shows a snapshot update or the diff against the snapshot.
+func printSnapshot(c *snapshotCheck) {
+	switch {
+	case c.Updated:
+		color.New(color.FgGreen).Printf("✔ Snapshot updated: %s\n", c.Path)
+	case len(c.Diff) > 0:
+		color.New(color.FgRed, color.Bold).Printf("\n✘ OG differs from snapshot %s:\n", c.Path)
+		printUnified(c.Diff)
+	}
+}
+
+// ------------------------------------------------------------------------------------------------
+// Snapshot Command
+// ------------------------------------------------------------------------------------------------
+
+func newSnapshotCmd() *cobra.Command {
+	var out string
+	var timeout int
+	var workers int
+
+	c := &cobra.Command{
+		Use:   "snapshot URL [URL...] --out DIR",
+		Short: "Store the parsed OG of each URL as sorted JSON for `validate --against` (use “-” to read from STDIN)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			urls, err := readURLs(args)
+			if err != nil {
+				return err
+			}
+			type result struct {
+				url, path string
+				err       error
+			}
+			failed := 0
+			for r := range runPool(urls, workers, func(u string) result {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+				defer cancel()
+				html, err := fetchHTML(ctx, u)
+				if err != nil {
+					return result{url: u, err: err}
+				}
+				path, err := writeSnapshot(out, u, parseOG(html))
+				return result{url: u, path: path, err: err}
+			}) {
+				if r.err != nil {
+					color.Red("Error snapshotting %s: %v", r.url, r.err)
+					failed++
+					continue
+				}
+				color.New(color.FgGreen).Printf("✔ %s", r.url)
+				color.New(color.FgHiBlack).Printf(" → %s\n", r.path)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d URL(s) could not be snapshotted", failed, len(urls))
+			}
+			return nil
+		},
+	}
+
+	c.Flags().StringVarP(&out, "out", "o", "", "Directory to write the snapshots to")
+	c.Flags().IntVarP(&timeout, "timeout", "t", int(defaultTimeout.Seconds()), "HTTP timeout in seconds")
+	c.Flags().IntVarP(&workers, "workers", "w", runtime.NumCPU(), "Number of concurrent workers")
+	_ = c.MarkFlagRequired("out")
+	return c
+}
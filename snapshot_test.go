@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	u := "https://example.com/post?id=1"
+	og := map[string]string{"title": "Hello", "type": "article", "image": "https://example.com/a.png"}
+
+	c := checkSnapshot(dir, u, og, false)
+	if ruleIDs(c.Findings) != "snapshot-missing" {
+		t.Fatalf("no snapshot: rules = %q", ruleIDs(c.Findings))
+	}
+	path, err := writeSnapshot(dir, u, og)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "\"image\": \"https://example.com/a.png\",\n    \"title\": \"Hello\",\n    \"type\": \"article\"") {
+		t.Errorf("snapshot is not sorted, indented JSON:\n%s", data)
+	}
+	if snapshotPath(dir, u) == snapshotPath(dir, "https://example.com/post?id=2") {
+		t.Error("URLs differing in the query share a snapshot file")
+	}
+
+	if c := checkSnapshot(dir, u, og, false); len(c.Findings) != 0 || len(c.Diff) != 0 {
+		t.Errorf("unchanged page: %+v", c)
+	}
+
+	live := map[string]string{"title": "Hello, world", "type": "article", "description": "New"}
+	c = checkSnapshot(dir, u, live, false)
+	if len(c.Diff) != 3 || ruleIDs(c.Findings) != "snapshot-changed snapshot-changed snapshot-changed" {
+		t.Fatalf("changed page: %+v", c)
+	}
+	want := []string{
+		`og:description was added: "New"`,
+		`og:image was removed (was "https://example.com/a.png")`,
+		`og:title changed from "Hello" to "Hello, world"`,
+	}
+	if got := strings.Join(messages(c.Findings), "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("messages:\n%s", got)
+	}
+
+	if c := checkSnapshot(dir, u, live, true); !c.Updated || len(c.Findings) != 0 {
+		t.Errorf("--update: %+v", c)
+	}
+	if c := checkSnapshot(dir, u, live, false); len(c.Findings) != 0 {
+		t.Errorf("after --update: %v", messages(c.Findings))
+	}
+
+	// Concurrent updates of one URL each replace the whole file and leave
+	// no temporary files behind.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := writeSnapshot(dir, u, og); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("snapshot dir holds %d files, want 1", len(entries))
+	}
+	if c := checkSnapshot(dir, u, og, false); len(c.Findings) != 0 {
+		t.Errorf("after concurrent writes: %v", messages(c.Findings))
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Client-Side Rendering Detection
+// ------------------------------------------------------------------------------------------------
+
+// spaReport explains why a page looks like a JavaScript shell whose OG tags
+// are injected client-side, where social crawlers never see them.
+type spaReport struct {
+	Likely   bool     `json:"likely"`
+	Evidence []string `json:"evidence"`
+}
+
+// spaRootSelectors are the mount points used by the common frameworks.
+var spaRootSelectors = []string{"#root", "#app", "#__next", "#___gatsby", "#__nuxt", "#svelte", "app-root"}
+
+// spaMarkers maps a framework to a selector that betrays it in server HTML.
+var spaMarkers = []struct{ name, selector string }{
+	{"React", "[data-reactroot]"},
+	{"Next.js", "script#__NEXT_DATA__"},
+	{"Nuxt", "script#__NUXT_DATA__"},
+	{"Angular", "[ng-version]"},
+	{"Vue", "[data-v-app]"},
+	{"Gatsby", "#___gatsby"},
+	{"react-helmet", "[data-react-helmet]"},
+	{"vue-meta", "[data-vmid]"},
+}
+
+// ogInTextRe finds OG property names inside script bodies and <noscript>.
+var ogInTextRe = regexp.MustCompile(`["'](og:[a-z_:]+)["']`)
+
+// bundleRe matches file names produced by the usual JS bundlers.
+var bundleRe = regexp.MustCompile(`(?i)(bundle|chunk|main|app|vendor|runtime)[.\-][\w.\-]*\.js`)
+
+// detectSPA applies the shell heuristics to html. A page is only flagged as
+// "likely" when essential tags are actually missing from the server response.
+func detectSPA(html string, og map[string]string) *spaReport {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	rep := &spaReport{}
+	var shell, tags int
+
+	// Empty mount point.
+	for _, sel := range spaRootSelectors {
+		root := doc.Find(sel).First()
+		if root.Length() > 0 && strings.TrimSpace(root.Text()) == "" && root.Children().Length() == 0 {
+			rep.Evidence = append(rep.Evidence, fmt.Sprintf("empty root element %s", sel))
+			shell++
+			break
+		}
+	}
+
+	// Script weight versus visible content.
+	var inlineBytes, external, bundles int
+	doc.Find("script").Each(func(_ int, s *goquery.Selection) {
+		if src, ok := s.Attr("src"); ok {
+			external++
+			if bundleRe.MatchString(src) {
+				bundles++
+			}
+			return
+		}
+		inlineBytes += len(s.Text())
+	})
+	bodyText := len(strings.TrimSpace(doc.Find("body").Clone().Find("script,style,noscript").Remove().End().Text()))
+	if bundles > 0 || external >= 5 || inlineBytes > 100<<10 {
+		rep.Evidence = append(rep.Evidence, fmt.Sprintf("%d external script(s) (%d bundle-like), %d KB inline script", external, bundles, inlineBytes>>10))
+		if bodyText < 200 {
+			shell++
+		}
+	}
+	if bodyText < 200 && external+inlineBytes > 0 {
+		rep.Evidence = append(rep.Evidence, fmt.Sprintf("only %d characters of server-rendered body text", bodyText))
+		shell++
+	}
+
+	// OG properties that only appear inside JSON state blobs or <noscript>.
+	seen := make(map[string]bool)
+	collect := func(where, text string) {
+		for _, m := range ogInTextRe.FindAllStringSubmatch(text, -1) {
+			key := strings.TrimPrefix(m[1], "og:")
+			if og[key] == "" && !seen[where+m[1]] {
+				seen[where+m[1]] = true
+				rep.Evidence = append(rep.Evidence, fmt.Sprintf("%s found only inside %s", m[1], where))
+				tags++
+			}
+		}
+	}
+	doc.Find("script").Each(func(_ int, s *goquery.Selection) {
+		if _, ok := s.Attr("src"); !ok {
+			collect("a script/JSON state blob", s.Text())
+		}
+	})
+	doc.Find("noscript").Each(func(_ int, s *goquery.Selection) {
+		collect("<noscript>", s.Text())
+	})
+
+	// Framework markers.
+	for _, m := range spaMarkers {
+		if doc.Find(m.selector).Length() > 0 {
+			rep.Evidence = append(rep.Evidence, fmt.Sprintf("%s marker (%s)", m.name, m.selector))
+		}
+	}
+
+	missing := false
+	for _, k := range builtinRules.essentials() {
+		if og[strings.TrimPrefix(k, "og:")] == "" {
+			missing = true
+			break
+		}
+	}
+	rep.Likely = missing && (tags > 0 || shell >= 2)
+	return rep
+}
+
+// finding summarises a likely SPA shell as a rule finding.
+func (r *spaReport) finding() finding {
+	return warnf("client-side-tags", "tags likely injected client-side: %s", strings.Join(r.Evidence, "; "))
+}
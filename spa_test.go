@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestDetectSPA(t *testing.T) {
+	shell := `<!doctype html><html><head><title>App</title>
+	<script id="__NEXT_DATA__" type="application/json">{"head":[["meta",{"property":"og:title","content":"Hi"}]]}</script>
+	</head><body><div id="__next"></div>
+	<script src="/_next/static/chunks/main-abc123.js"></script></body></html>`
+	rep := detectSPA(shell, parseOG(shell))
+	if rep == nil || !rep.Likely {
+		t.Fatalf("detectSPA: expected SPA shell to be flagged, got %+v", rep)
+	}
+
+	ssr := `<!doctype html><html><head>
+	<meta property="og:title" content="Hi"><meta property="og:type" content="website">
+	<meta property="og:image" content="https://example.com/a.png"><meta property="og:url" content="https://example.com">
+	<meta property="og:description" content="Desc">
+	</head><body><div id="root"></div><script src="/static/js/main.1234.js"></script></body></html>`
+	if rep := detectSPA(ssr, parseOG(ssr)); rep == nil || rep.Likely {
+		t.Errorf("detectSPA: page with server-side tags must not be flagged, got %+v", rep)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/fatih/color"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Fix-it Snippets
+// ------------------------------------------------------------------------------------------------
+
+// suggestFlavors are the snippet syntaxes of --suggest.
+var suggestFlavors = []string{"html", "jsx", "gotmpl"}
+
+// maxImageCandidates bounds how many page images are probed for the largest.
+const maxImageCandidates = 8
+
+// suggestion is one proposed meta tag.
+type suggestion struct {
+	Property string
+	Value    string // empty for a placeholder
+	Hint     string // what to fill in for a placeholder
+}
+
+// checkSuggestFlavor validates a --suggest value.
+func checkSuggestFlavor(flavor string) error {
+	for _, f := range suggestFlavors {
+		if flavor == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown --suggest flavour %q (want %s)", flavor, strings.Join(suggestFlavors, ", "))
+}
+
+// suggestProperties lists, in finding order and without repeats, the
+// properties that are missing or fail a rule's pattern or length.
+func suggestProperties(fs []finding) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range fs {
+		if f.Property == "" || seen[f.Property] || strings.HasPrefix(f.Rule, "snapshot-") {
+			continue
+		}
+		if f.Missing || strings.HasSuffix(f.Rule, "-pattern") || strings.HasSuffix(f.Rule, "-length") {
+			seen[f.Property] = true
+			out = append(out, f.Property)
+		}
+	}
+	return out
+}
+
+// largestImage returns the biggest usable image the page references: the
+// image_src link, twitter:image and <img> sources, probed concurrently for
+// their dimensions within ctx. It falls back to the first candidate when none
+// can be probed.
+func largestImage(ctx context.Context, page, base string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return ""
+	}
+	var candidates []string
+	add := func(ref string, ok bool) {
+		if ref = strings.TrimSpace(ref); ok && ref != "" && !strings.HasPrefix(ref, "data:") && len(candidates) < maxImageCandidates {
+			u := resolveURL(base, ref)
+			for _, c := range candidates {
+				if c == u {
+					return
+				}
+			}
+			candidates = append(candidates, u)
+		}
+	}
+	add(doc.Find(`link[rel="image_src"]`).First().Attr("href"))
+	add(doc.Find(`meta[name="twitter:image"], meta[property="twitter:image"]`).First().Attr("content"))
+	doc.Find("body img[src]").Each(func(_ int, s *goquery.Selection) { add(s.Attr("src")) })
+	if len(candidates) <= 1 {
+		return strings.Join(candidates, "")
+	}
+
+	areas := make([]int, len(candidates))
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if info, err := probeImage(ctx, c); err == nil {
+				areas[i] = info.Width * info.Height
+			}
+		}()
+	}
+	wg.Wait()
+
+	best, bestArea := candidates[0], 0
+	for i, c := range candidates {
+		if areas[i] > bestArea {
+			best, bestArea = c, areas[i]
+		}
+	}
+	return best
+}
+
+// buildSuggestions proposes a value for each property from the page's
+// fallbacks (<title>, the meta description, rel=canonical, the largest image,
+// <html lang>) or a sensible default, and a placeholder hint elsewhere.
+func buildSuggestions(props []string, page, pageURL string, fb fallbackData, image string) []suggestion {
+	lang := ""
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(page)); err == nil {
+		lang, _ = doc.Find("html").Attr("lang")
+	}
+	var out []suggestion
+	for _, p := range props {
+		s := suggestion{Property: p}
+		_, name, _ := strings.Cut(p, ":")
+		switch name {
+		case "title":
+			s.Value = fb.Title
+		case "description":
+			s.Value = fb.Description
+		case "url":
+			s.Value = pick(fb.Canonical, pageURL)
+		case "image":
+			s.Value = image
+		case "type":
+			s.Value = "website"
+		case "card":
+			s.Value = "summary_large_image"
+		case "locale":
+			if l := localeFromTag(lang); ogLocaleRe.MatchString(l) {
+				s.Value = l
+			}
+		}
+		if s.Value == "" {
+			s.Hint = placeholderHint(p)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// placeholderHint describes what a property expects, e.g. "site name" for
+// og:site_name.
+func placeholderHint(property string) string {
+	_, name, _ := strings.Cut(property, ":")
+	return strings.NewReplacer("_", " ", ":", " ").Replace(name)
+}
+
+// templateField turns a property into a Go template field name:
+// og:site_name becomes SiteName, og:image:alt becomes ImageAlt.
+func templateField(property string) string {
+	_, name, _ := strings.Cut(property, ":")
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == ':' || r == '.' || r == '-' }) {
+		rs := []rune(part)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	return b.String()
+}
+
+// renderSuggestions renders the meta block in flavour: plain HTML, JSX
+// (self-closing tags, {/* */} comments) or a Go html/template in which
+// placeholders become {{.Field}} actions.
+func renderSuggestions(ss []suggestion, flavor string) string {
+	var b strings.Builder
+	switch flavor {
+	case "jsx":
+		b.WriteString("{/* Open Graph tags suggested by ogspy; replace the TODO values */}\n")
+	case "gotmpl":
+		b.WriteString("{{/* Open Graph tags suggested by ogspy; fill in the template fields */}}\n")
+	default:
+		b.WriteString("<!-- Open Graph tags suggested by ogspy; replace the TODO values -->\n")
+	}
+	for _, s := range ss {
+		attr := "property"
+		if strings.HasPrefix(s.Property, "twitter:") {
+			attr = "name"
+		}
+		content := html.EscapeString(s.Value)
+		if flavor == "gotmpl" {
+			// A literal "{{" would open an action.
+			content = strings.ReplaceAll(content, "{{", `{{"{{"}}`)
+		}
+		if s.Value == "" {
+			content = "TODO: " + html.EscapeString(s.Hint)
+			if flavor == "gotmpl" {
+				content = "{{." + templateField(s.Property) + "}}"
+			}
+		}
+		end := ">"
+		if flavor == "jsx" {
+			end = " />"
+		}
+		fmt.Fprintf(&b, "<meta %s=\"%s\" content=\"%s\"%s\n", attr, s.Property, content, end)
+	}
+	return b.String()
+}
+
+// suggestSnippet builds the ready-to-paste block for the properties fs
+// reports as missing or invalid, or "" when there are none. ctx bounds the
+// probing of page images for og:image.
+func suggestSnippet(ctx context.Context, fs []finding, page, pageURL string, fb fallbackData, flavor string) string {
+	props := suggestProperties(fs)
+	if len(props) == 0 {
+		return ""
+	}
+	image := ""
+	for _, p := range props {
+		if strings.HasSuffix(p, ":image") {
+			image = pick(largestImage(ctx, page, pageURL), fb.Image)
+			break
+		}
+	}
+	return renderSuggestions(buildSuggestions(props, page, pageURL, fb, image), flavor)
+}
+
+// printSuggestion shows the snippet below the missing-tags list.
+func printSuggestion(snippet string) {
+	if snippet == "" {
+		return
+	}
+	color.New(color.FgCyan, color.Bold).Println("\nSuggested tags:")
+	fmt.Print(snippet)
+}
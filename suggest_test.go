@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLargestImage(t *testing.T) {
+	encode := func(w, h int) []byte {
+		var buf bytes.Buffer
+		_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)))
+		return buf.Bytes()
+	}
+	small, large := encode(64, 64), encode(1200, 630)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		switch r.URL.Path {
+		case "/logo.png":
+			w.Write(small)
+		case "/hero.png":
+			w.Write(large)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	page := `<html><body><img src="/logo.png"><img src="/missing.png"><img src="/hero.png"><img src="data:image/gif;base64,R0lGOD"></body></html>`
+	if got := largestImage(context.Background(), page, srv.URL+"/post"); got != srv.URL+"/hero.png" {
+		t.Errorf("largestImage = %q, want the hero image", got)
+	}
+}
+
+func TestSuggestSnippet(t *testing.T) {
+	fs := []finding{
+		{Rule: "og-title-missing", Severity: sevError, Property: "og:title", Missing: true},
+		{Rule: "og-url-missing", Severity: sevError, Property: "og:url", Missing: true},
+		{Rule: "og-site_name-missing", Severity: sevError, Property: "og:site_name", Missing: true},
+		{Rule: "og-locale-missing", Severity: sevError, Property: "og:locale", Missing: true},
+		{Rule: "snapshot-changed", Severity: sevError, Property: "og:description"},
+		warnf("image-ratio", "ratio"),
+	}
+	page := `<html lang="de-at"><head><title>Tom & Jerry "Live"</title></head></html>`
+	fb := fallbackData{Title: `Tom & Jerry "Live"`, Canonical: "https://example.com/show"}
+
+	got := suggestSnippet(context.Background(), fs, page, "https://example.com/show?ref=x", fb, "html")
+	want := `<!-- Open Graph tags suggested by ogspy; replace the TODO values -->
+<meta property="og:title" content="Tom &amp; Jerry &#34;Live&#34;">
+<meta property="og:url" content="https://example.com/show">
+<meta property="og:site_name" content="TODO: site name">
+<meta property="og:locale" content="de_AT">
+`
+	if got != want {
+		t.Errorf("html snippet:\n%s\nwant:\n%s", got, want)
+	}
+
+	jsx := suggestSnippet(context.Background(), fs, page, "https://example.com/show", fb, "jsx")
+	if !strings.HasPrefix(jsx, "{/*") || !strings.Contains(jsx, `<meta property="og:site_name" content="TODO: site name" />`) {
+		t.Errorf("jsx snippet:\n%s", jsx)
+	}
+	tmpl := suggestSnippet(context.Background(), fs, page, "https://example.com/show", fb, "gotmpl")
+	if !strings.HasPrefix(tmpl, "{{/*") || !strings.Contains(tmpl, `content="{{.SiteName}}"`) {
+		t.Errorf("gotmpl snippet:\n%s", tmpl)
+	}
+
+	// Page text with "{{" still yields a template that parses and renders it.
+	braces := fallbackData{Title: "Use {{ and }} in templates"}
+	tmpl = suggestSnippet(context.Background(), fs[:1], page, "https://example.com/show", braces, "gotmpl")
+	var out strings.Builder
+	if tp, err := template.New("og").Parse(tmpl); err != nil {
+		t.Errorf("gotmpl snippet does not parse: %v\n%s", err, tmpl)
+	} else if err := tp.Execute(&out, nil); err != nil || !strings.Contains(out.String(), `content="Use {{ and }} in templates"`) {
+		t.Errorf("gotmpl snippet renders %q, %v", out.String(), err)
+	}
+
+	if s := suggestSnippet(context.Background(), []finding{warnf("image-ratio", "ratio")}, page, "https://example.com/", fb, "html"); s != "" {
+		t.Errorf("nothing to fix, got:\n%s", s)
+	}
+	if templateField("og:image:alt") != "ImageAlt" || checkSuggestFlavor("pug") == nil {
+		t.Error("templateField/checkSuggestFlavor")
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Text Length & Truncation
+// ------------------------------------------------------------------------------------------------
+
+// textFit is how one platform displays a title or description.
+type textFit struct {
+	Platform  string `json:"platform"`
+	Field     string `json:"field"` // "title" or "description"
+	Shown     string `json:"shown"`
+	Chars     int    `json:"chars"`
+	Graphemes int    `json:"graphemes"`
+	WidthPx   int    `json:"width_px,omitempty"` // approximate, only for width-truncating platforms
+	Limit     string `json:"limit,omitempty"`    // the limit that cut the text, e.g. "70 characters" or "480 px"
+	Truncated bool   `json:"truncated"`
+}
+
+const zwj = '\u200d' // zero-width joiner
+
+// graphemes splits s into approximate extended grapheme clusters: a base
+// character with its combining marks, variation selectors and emoji
+// modifiers, ZWJ emoji sequences, regional-indicator flag pairs and CRLF.
+func graphemes(s string) []string {
+	rs := []rune(s)
+	var out []string
+	for i := 0; i < len(rs); {
+		j := i + 1
+		switch {
+		case rs[i] == '\r' && j < len(rs) && rs[j] == '\n':
+			j++
+		case isRegionalIndicator(rs[i]) && j < len(rs) && isRegionalIndicator(rs[j]):
+			j++
+		}
+		for j < len(rs) && (extendsGrapheme(rs[j]) || rs[j-1] == zwj) {
+			j++
+		}
+		out = append(out, string(rs[i:j]))
+		i = j
+	}
+	return out
+}
+
+func isRegionalIndicator(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+// extendsGrapheme reports whether r attaches to the preceding character.
+func extendsGrapheme(r rune) bool {
+	return r == zwj ||
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // emoji skin-tone modifiers
+		(r >= 0xE0020 && r <= 0xE007F) // emoji tag sequences
+}
+
+// charWidth approximates the advance width of r in ems for the Helvetica /
+// Arial family most cards use.
+func charWidth(r rune) float64 {
+	switch {
+	case r == ' ':
+		return 0.28
+	case strings.ContainsRune("ijlI.,:;'|!`", r):
+		return 0.25
+	case strings.ContainsRune(`ftr()[]{}"-/`, r):
+		return 0.33
+	case strings.ContainsRune("mwMW", r):
+		return 0.85
+	case r >= 'A' && r <= 'Z':
+		return 0.67
+	case r >= 'a' && r <= 'z':
+		return 0.52
+	case r >= '0' && r <= '9':
+		return 0.56
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r >= 0x1F000:
+		return 1
+	}
+	return 0.56
+}
+
+// textWidth approximates the rendered width of s in pixels.
+func textWidth(s string, fontPx float64) float64 {
+	var em float64
+	for _, g := range graphemes(s) {
+		r, _ := utf8.DecodeRuneInString(g)
+		em += charWidth(r)
+	}
+	return em * fontPx
+}
+
+// cutWords keeps the first n graphemes, backs off to the last word boundary
+// when it is past the middle, and appends an ellipsis.
+func cutWords(gs []string, n int) string {
+	r := strings.Join(gs[:max(n, 0)], "")
+	if i := strings.LastIndexByte(r, ' '); i > len(r)/2 {
+		return strings.TrimRight(r[:i], " ,.;:-") + "…"
+	}
+	return r + "…"
+}
+
+// fit applies p's character and pixel limits to a title or description.
+func (p platform) fit(field, s string) textFit {
+	s = strings.Join(strings.Fields(s), " ")
+	limit, limitPx := p.TitleMax, p.TitleMaxPx
+	if field == "description" {
+		limit, limitPx = p.DescMax, 0
+	}
+	gs := graphemes(s)
+	f := textFit{Platform: p.Key, Field: field, Shown: s, Chars: utf8.RuneCountInString(s), Graphemes: len(gs)}
+
+	keep := len(gs)
+	if limit > 0 && len(gs) > limit {
+		keep, f.Limit = limit-1, fmt.Sprintf("%d characters", limit)
+	}
+	if limitPx > 0 {
+		f.WidthPx = int(math.Round(textWidth(s, p.FontPx)))
+		if f.WidthPx > limitPx {
+			budget := float64(limitPx) - textWidth("…", p.FontPx)
+			n := 0
+			for w := 0.0; n < len(gs); n++ {
+				r, _ := utf8.DecodeRuneInString(gs[n])
+				if w += charWidth(r) * p.FontPx; w > budget {
+					break
+				}
+			}
+			if n < keep {
+				keep, f.Limit = n, fmt.Sprintf("%d px", limitPx)
+			}
+		}
+	}
+	if keep < len(gs) {
+		f.Shown, f.Truncated = cutWords(gs, keep), true
+	}
+	return f
+}
+
+// textFits previews the title and (where shown) the description on every
+// platform, using the same source resolution as the HTML report cards.
+func textFits(src cardSources) []textFit {
+	var out []textFit
+	for _, p := range platforms {
+		title, desc, _ := p.texts(src)
+		if title != "" {
+			out = append(out, p.fit("title", title))
+		}
+		if desc != "" && p.DescMax > 0 {
+			out = append(out, p.fit("description", desc))
+		}
+	}
+	return out
+}
+
+// fitFindings reports every truncated text with the string the platform
+// actually shows. Cut titles are warnings; cut descriptions are common and
+// reported as info.
+func fitFindings(fits []textFit) []finding {
+	var out []finding
+	names := make(map[string]string, len(platforms))
+	for _, p := range platforms {
+		names[p.Key] = p.Name
+	}
+	for _, f := range fits {
+		if !f.Truncated {
+			continue
+		}
+		size := fmt.Sprintf("%d characters, %d graphemes", f.Chars, f.Graphemes)
+		if f.WidthPx > 0 {
+			size += fmt.Sprintf(", ≈%d px", f.WidthPx)
+		}
+		build := warnf
+		if f.Field == "description" {
+			build = infof
+		}
+		out = append(out, build(f.Field+"-truncated-"+f.Platform, "%s is cut by %s at %s (%s): %q", f.Field, names[f.Platform], f.Limit, size, f.Shown))
+	}
+	return out
+}
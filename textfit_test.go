@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGraphemes(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+	}{
+		{"hello", 5},
+		{"cafe\u0301", 4}, // e + combining acute
+		{"\U0001F1EE\U0001F1F9\U0001F1EB\U0001F1F7", 2},                   // two flags
+		{"\U0001F469\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466", 1}, // ZWJ family
+		{"\U0001F44D\U0001F3FD ok", 4},                                    // skin-tone modifier
+		{"\u2764\ufe0f\r\n", 2},                                           // variation selector, CRLF
+	}
+	for _, c := range cases {
+		if got := len(graphemes(c.in)); got != c.want {
+			t.Errorf("graphemes(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestPlatformFit(t *testing.T) {
+	var wa, x platform
+	for _, p := range platforms {
+		switch p.Key {
+		case "whatsapp":
+			wa = p
+		case "x":
+			x = p
+		}
+	}
+
+	// Emoji clusters count once and are never split.
+	title := strings.Repeat("👩‍👩‍👧 ", 40)
+	f := wa.fit("title", title)
+	if !f.Truncated || f.Graphemes != 79 || !strings.HasSuffix(f.Shown, "…") || !utf8.ValidString(f.Shown) {
+		t.Errorf("whatsapp fit = %+v", f)
+	}
+	if n := len(graphemes(f.Shown)); n > wa.TitleMax {
+		t.Errorf("shown title has %d graphemes, limit %d", n, wa.TitleMax)
+	}
+
+	// Wide capitals overflow X's pixel budget before its character limit.
+	caps := strings.Repeat("WIDE ", 13) // 65 characters
+	if f := x.fit("title", caps); !f.Truncated || f.Limit != "480 px" || f.WidthPx <= 480 {
+		t.Errorf("x fit = %+v, want a pixel truncation", f)
+	}
+	if f := x.fit("title", "Short title"); f.Truncated || f.WidthPx == 0 {
+		t.Errorf("x short fit = %+v", f)
+	}
+}
+
+func TestFitFindings(t *testing.T) {
+	src := cardSources{
+		pageURL: "https://example.com/",
+		og: map[string]string{
+			"title":       strings.Repeat("Our editors keep writing very long titles ", 4),
+			"description": "Short.",
+		},
+	}
+	fs := fitFindings(textFits(src))
+	var ids []string
+	for _, f := range fs {
+		ids = append(ids, f.Rule)
+	}
+	joined := strings.Join(ids, " ")
+	for _, want := range []string{"title-truncated-facebook", "title-truncated-x", "title-truncated-whatsapp"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("fitFindings rules %v lack %s", ids, want)
+		}
+	}
+	if strings.Contains(joined, "description-truncated") || strings.Contains(joined, "title-truncated-discord") {
+		t.Errorf("unexpected truncation findings: %v", ids)
+	}
+}
+
+func TestGraphemeLengthRule(t *testing.T) {
+	rs, err := parseRules([]byte(`{"rules": [{"property": "og:title", "max_length": 3, "length_unit": "graphemes"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs := rs.check(map[string]string{"og:title": "🇮🇹🇫🇷🇩🇪"}, false, false); len(fs) != 0 {
+		t.Errorf("three flags counted as more than 3 graphemes: %v", fs)
+	}
+	if _, err := parseRules([]byte(`{"rules": [{"property": "og:title", "length_unit": "bytes"}]}`)); err == nil {
+		t.Error("parseRules accepted length_unit bytes")
+	}
+}
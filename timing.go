@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Network Timing
+// ------------------------------------------------------------------------------------------------
+
+// timings is the per-request breakdown captured through httptrace. Phases that
+// did not happen (e.g. DNS and TLS on a reused connection) stay at zero.
+type timings struct {
+	DNS      time.Duration
+	Connect  time.Duration
+	TLS      time.Duration
+	TTFB     time.Duration
+	Download time.Duration
+	Total    time.Duration
+}
+
+// MarshalJSON renders every phase in milliseconds.
+func (t timings) MarshalJSON() ([]byte, error) {
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	return json.Marshal(struct {
+		DNS      float64 `json:"dns_ms"`
+		Connect  float64 `json:"connect_ms"`
+		TLS      float64 `json:"tls_ms"`
+		TTFB     float64 `json:"ttfb_ms"`
+		Download float64 `json:"download_ms"`
+		Total    float64 `json:"total_ms"`
+	}{ms(t.DNS), ms(t.Connect), ms(t.TLS), ms(t.TTFB), ms(t.Download), ms(t.Total)})
+}
+
+// String renders a compact one-line breakdown for the table output.
+func (t timings) String() string {
+	r := func(d time.Duration) string { return d.Round(time.Millisecond).String() }
+	return fmt.Sprintf("dns %s · connect %s · tls %s · ttfb %s · download %s · total %s",
+		r(t.DNS), r(t.Connect), r(t.TLS), r(t.TTFB), r(t.Download), r(t.Total))
+}
+
+// timingTrace collects httptrace events for a single request. Dial callbacks
+// may fire more than once (happy eyeballs), so only the first pair counts.
+type timingTrace struct {
+	mu                  sync.Mutex
+	start, firstByte    time.Time
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+}
+
+// traceContext attaches a timing trace to ctx; call done() once the response
+// body has been consumed.
+func traceContext(ctx context.Context) (context.Context, *timingTrace) {
+	tt := &timingTrace{start: time.Now()}
+	set := func(dst *time.Time) {
+		tt.mu.Lock()
+		if dst.IsZero() {
+			*dst = time.Now()
+		}
+		tt.mu.Unlock()
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { set(&tt.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { set(&tt.dnsDone) },
+		ConnectStart:         func(string, string) { set(&tt.connStart) },
+		ConnectDone:          func(_, _ string, _ error) { set(&tt.connDone) },
+		TLSHandshakeStart:    func() { set(&tt.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&tt.tlsDone) },
+		GotFirstResponseByte: func() { set(&tt.firstByte) },
+	}
+	return httptrace.WithClientTrace(ctx, trace), tt
+}
+
+// done returns the breakdown measured up to now.
+func (tt *timingTrace) done() timings {
+	end := time.Now()
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	span := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() {
+			return 0
+		}
+		return to.Sub(from)
+	}
+	return timings{
+		DNS:      span(tt.dnsStart, tt.dnsDone),
+		Connect:  span(tt.connStart, tt.connDone),
+		TLS:      span(tt.tlsStart, tt.tlsDone),
+		TTFB:     span(tt.start, tt.firstByte),
+		Download: span(tt.firstByte, end),
+		Total:    end.Sub(tt.start),
+	}
+}
+
+// timeFetch downloads url (up to 5 MB) purely to measure how long a crawler
+// would wait for it.
+func timeFetch(ctx context.Context, url string) (timings, error) {
+	ctx, tt := traceContext(ctx)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return timings{}, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return timings{}, err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, io.LimitReader(resp.Body, 5<<20)); err != nil {
+		return timings{}, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return tt.done(), fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+	return tt.done(), nil
+}
+
+// ------------------------------------------------------------------------------------------------
+// Crawler Budget
+// ------------------------------------------------------------------------------------------------
+
+// crawlerBudget holds the thresholds beyond which social crawlers are likely
+// to give up and render an empty preview.
+type crawlerBudget struct {
+	TTFB  time.Duration
+	Total time.Duration
+}
+
+// defaultBudget mirrors the conservative end of what the major crawlers tolerate.
+var defaultBudget = crawlerBudget{TTFB: time.Second, Total: 3 * time.Second}
+
+// addFlags registers the budget flags on a command.
+func (b *crawlerBudget) addFlags(c *cobra.Command) {
+	c.Flags().DurationVar(&b.Total, "crawler-budget", defaultBudget.Total, "Warn when a page or image takes longer than this to download")
+	c.Flags().DurationVar(&b.TTFB, "ttfb-budget", defaultBudget.TTFB, "Warn when time-to-first-byte exceeds this")
+}
+
+// budgetWarnings reports every phase of t that crosses the budget; label names
+// the resource ("page", "og:image", ...).
+func budgetWarnings(label string, t timings, b crawlerBudget) []finding {
+	var warns []finding
+	if b.TTFB > 0 && t.TTFB > b.TTFB {
+		warns = append(warns, warnf("budget-ttfb", "%s TTFB %s exceeds crawler budget of %s", label, t.TTFB.Round(time.Millisecond), b.TTFB))
+	}
+	if b.Total > 0 && t.Total > b.Total {
+		warns = append(warns, warnf("budget-total", "%s took %s, exceeding crawler budget of %s", label, t.Total.Round(time.Millisecond), b.Total))
+	}
+	return warns
+}
+
+// imageURLs returns every image URL declared by the OG map, resolved against
+// the page URL base, in a stable order.
+func imageURLs(og map[string]string, base string) []string {
+	var urls []string
+	for _, k := range []string{"image", "image:url", "image:secure_url"} {
+		v := strings.TrimSpace(og[k])
+		if v == "" {
+			continue
+		}
+		if v = resolveURL(base, v); !slices.Contains(urls, v) {
+			urls = append(urls, v)
+		}
+	}
+	return urls
+}
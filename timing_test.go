@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFetchPageTiming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("<html><head></head><body>ok</body></html>"))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	p, err := fetchPage(ctx, srv.URL)
+	if err != nil {
+		t.Fatalf("fetchPage error: %v", err)
+	}
+	if p.Timing.TTFB < 20*time.Millisecond {
+		t.Errorf("TTFB = %s, want ≥ 20ms", p.Timing.TTFB)
+	}
+	if p.Timing.Total < p.Timing.TTFB {
+		t.Errorf("Total %s < TTFB %s", p.Timing.Total, p.Timing.TTFB)
+	}
+}
+
+func TestBudgetWarnings(t *testing.T) {
+	b := crawlerBudget{TTFB: time.Second, Total: 3 * time.Second}
+
+	if w := budgetWarnings("page", timings{TTFB: 200 * time.Millisecond, Total: time.Second}, b); len(w) != 0 {
+		t.Errorf("budgetWarnings: unexpected warnings %v", w)
+	}
+	if w := budgetWarnings("page", timings{TTFB: 2 * time.Second, Total: 4 * time.Second}, b); len(w) != 2 {
+		t.Errorf("budgetWarnings: got %d warnings, want 2", len(w))
+	}
+}
+
+func TestImageURLs(t *testing.T) {
+	og := map[string]string{"image": "/img/card.png", "image:url": "https://example.com/img/card.png", "image:secure_url": "//cdn.example.com/card.png"}
+	got := imageURLs(og, "https://example.com/post/1")
+	want := []string{"https://example.com/img/card.png", "https://cdn.example.com/card.png"}
+	if !slices.Equal(got, want) {
+		t.Errorf("imageURLs = %v, want %v", got, want)
+	}
+}
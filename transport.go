@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ------------------------------------------------------------------------------------------------
+// Shared HTTP Transport
+// ------------------------------------------------------------------------------------------------
+
+// httpClient is shared by every page and image fetch. It is rebuilt in
+// newRootCmd().PersistentPreRunE from the global network flags.
+var httpClient = &http.Client{Timeout: defaultTimeout}
+
+// transportOptions groups the persistent network flags.
+type transportOptions struct {
+	resolve   []string // curl-style "host:port:addr" overrides
+	dnsServer string   // "addr[:port]" of a custom DNS resolver
+	policy    fetchPolicy
+}
+
+// dialer routes outgoing connections through the --resolve overrides, the
+// optional custom resolver and the fetch policy. The request host is left
+// untouched, so SNI, the Host header and absolute og:url values keep pointing
+// at the original name.
+type dialer struct {
+	overrides map[string]string // "host:port" → "addr:port"
+	policy    *fetchPolicy
+	net       net.Dialer
+}
+
+// DialContext implements the http.Transport dial hook.
+func (d *dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	target := address
+	if t, ok := d.overrides[strings.ToLower(address)]; ok {
+		if logger != nil {
+			logger.Debug("http.resolve", slog.String("address", address), slog.String("target", t))
+		}
+		target = t
+	}
+	if d.policy != nil && d.policy.active() {
+		return d.dialSafe(ctx, network, address, target)
+	}
+	return d.net.DialContext(ctx, network, target)
+}
+
+// parseResolve converts curl-style "host:port:addr" specs into a dial
+// override table. IPv6 addresses may be given with or without brackets.
+func parseResolve(specs []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid --resolve %q (want host:port:addr)", spec)
+		}
+		port, err := strconv.Atoi(parts[1])
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid --resolve %q: bad port %q", spec, parts[1])
+		}
+		addr := strings.TrimSuffix(strings.TrimPrefix(parts[2], "["), "]")
+		if net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid --resolve %q: %q is not an IP address", spec, parts[2])
+		}
+		key := net.JoinHostPort(strings.ToLower(parts[0]), parts[1])
+		overrides[key] = net.JoinHostPort(addr, parts[1])
+	}
+	return overrides, nil
+}
+
+// newResolver returns a pure-Go resolver that sends every query to server
+// ("addr" or "addr:port", port 53 by default).
+func newResolver(server string) (*net.Resolver, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+	}
+	host, _, _ := net.SplitHostPort(server)
+	if net.ParseIP(host) == nil {
+		return nil, fmt.Errorf("invalid --dns-server %q: not an IP address", server)
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}, nil
+}
+
+// newHTTPClient builds the shared client from the global network flags.
+func newHTTPClient(opts transportOptions) (*http.Client, error) {
+	overrides, err := parseResolve(opts.resolve)
+	if err != nil {
+		return nil, err
+	}
+	d := &dialer{
+		overrides: overrides,
+		policy:    &opts.policy,
+		net:       net.Dialer{Timeout: defaultTimeout, KeepAlive: 30 * time.Second},
+	}
+	if opts.dnsServer != "" {
+		if d.net.Resolver, err = newResolver(opts.dnsServer); err != nil {
+			return nil, err
+		}
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.DialContext = d.DialContext
+	client := &http.Client{Transport: tr, Timeout: defaultTimeout}
+	if opts.policy.active() {
+		// A proxy would hide the real destination from the dial-time checks.
+		tr.Proxy = nil
+		client.CheckRedirect = opts.policy.checkRedirect
+	}
+	return client, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseResolve(t *testing.T) {
+	got, err := parseResolve([]string{"Example.com:443:203.0.113.7", "example.org:80:[2001:db8::1]"})
+	if err != nil {
+		t.Fatalf("parseResolve error: %v", err)
+	}
+	if got["example.com:443"] != "203.0.113.7:443" {
+		t.Errorf("IPv4 override = %q", got["example.com:443"])
+	}
+	if got["example.org:80"] != "[2001:db8::1]:80" {
+		t.Errorf("IPv6 override = %q", got["example.org:80"])
+	}
+
+	for _, bad := range []string{"example.com", "example.com:x:1.2.3.4", "example.com:443:not-an-ip"} {
+		if _, err := parseResolve([]string{bad}); err == nil {
+			t.Errorf("parseResolve(%q): expected error", bad)
+		}
+	}
+}
+
+func TestResolveOverrideKeepsHost(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		_, _ = w.Write([]byte("<html><head></head><body>ok</body></html>"))
+	}))
+	defer srv.Close()
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	client, err := newHTTPClient(transportOptions{resolve: []string{"www.example.test:" + port + ":127.0.0.1"}})
+	if err != nil {
+		t.Fatalf("newHTTPClient error: %v", err)
+	}
+	prev := httpClient
+	httpClient = client
+	defer func() { httpClient = prev }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := fetchHTML(ctx, "http://www.example.test:"+port+"/"); err != nil {
+		t.Fatalf("fetchHTML error: %v", err)
+	}
+	if gotHost != "www.example.test:"+port {
+		t.Errorf("Host header = %q, want original hostname", gotHost)
+	}
+}